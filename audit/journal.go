@@ -0,0 +1,212 @@
+// Package audit keeps an append-only JSON-lines journal of the changes made
+// to Clockify through the server.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Record describes one tool call that changed, or tried to change, Clockify
+// data.
+type Record struct {
+	ID   string         `json:"id"`
+	Time time.Time      `json:"time"`
+	Tool string         `json:"tool"`
+	Args map[string]any `json:"args,omitempty"`
+
+	// UserID and UserName identify the Clockify user whose API key made the
+	// change; Client is the MCP client name the caller reported, if any.
+	UserID   string `json:"user_id,omitempty"`
+	UserName string `json:"user_name,omitempty"`
+	Client   string `json:"client,omitempty"`
+
+	// Entity is the kind of object changed (time_entry, project, task, tag or
+	// client). Before and After are its state around the call; Before is
+	// empty for creates and After for deletes.
+	WorkspaceID string          `json:"workspace_id,omitempty"`
+	Entity      string          `json:"entity,omitempty"`
+	EntityID    string          `json:"entity_id,omitempty"`
+	Before      json.RawMessage `json:"before,omitempty"`
+	After       json.RawMessage `json:"after,omitempty"`
+
+	// Responses lists Clockify's answer to every write the call sent, in
+	// order. Error is the tool error returned to the caller, if any.
+	Responses []Response `json:"responses"`
+	Error     string     `json:"error,omitempty"`
+
+	// Undoes is the ID of the record this one reverted, for changes made
+	// by an undo.
+	Undoes string `json:"undoes,omitempty"`
+}
+
+// Response is Clockify's answer to one request that changed data.
+type Response struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+}
+
+// Journal appends records to a JSON-lines file. It is safe for concurrent
+// use.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	now  func() time.Time
+}
+
+// Open opens the journal at path for appending, creating the file and its
+// directory if needed.
+func Open(path string) (*Journal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create audit log directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	if err := endLine(f, path); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &Journal{path: path, file: f, now: time.Now}, nil
+}
+
+// endLine terminates a last line left unfinished by a crash, so the next
+// record starts on a line of its own.
+func endLine(f *os.File, path string) error {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	r, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer r.Close()
+	last := make([]byte, 1)
+	if _, err := r.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("read audit log: %w", err)
+	}
+	if last[0] != '\n' {
+		_, err = f.Write([]byte{'\n'})
+	}
+	return err
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	return j.file.Close()
+}
+
+// Append writes rec as one line, filling in its ID and time if unset.
+func (j *Journal) Append(rec *Record) error {
+	if rec.ID == "" {
+		rec.ID = newID()
+	}
+	if rec.Time.IsZero() {
+		rec.Time = j.now().UTC()
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+	line = append(line, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.file.Write(line); err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+	return nil
+}
+
+// Query selects records. Zero fields match everything.
+type Query struct {
+	// Tool is a path.Match pattern over tool names.
+	Tool     string
+	Entity   string
+	EntityID string
+	UserID   string
+	// Since and Until bound the record time, Until exclusive.
+	Since time.Time
+	Until time.Time
+	// Text matches records whose JSON contains it, ignoring case.
+	Text string
+	// Limit caps the result; zero or less returns every match.
+	Limit int
+}
+
+func (q Query) matches(rec Record, line []byte) bool {
+	if q.Tool != "" {
+		if ok, _ := path.Match(q.Tool, rec.Tool); !ok {
+			return false
+		}
+	}
+	switch {
+	case q.Entity != "" && rec.Entity != q.Entity,
+		q.EntityID != "" && rec.EntityID != q.EntityID,
+		q.UserID != "" && rec.UserID != q.UserID,
+		!q.Since.IsZero() && rec.Time.Before(q.Since),
+		!q.Until.IsZero() && !rec.Time.Before(q.Until):
+		return false
+	}
+	return q.Text == "" || bytes.Contains(bytes.ToLower(line), []byte(strings.ToLower(q.Text)))
+}
+
+// Query returns the records matching q, newest first. Lines that do not
+// parse, such as one cut short by a crash, are skipped.
+func (j *Journal) Query(q Query) ([]Record, error) {
+	f, err := os.Open(j.path)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var matched []Record
+	br := bufio.NewReader(f)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var rec Record
+			if json.Unmarshal(line, &rec) == nil && q.matches(rec, line) {
+				matched = append(matched, rec)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read audit log: %w", err)
+		}
+	}
+
+	out := make([]Record, 0, len(matched))
+	for i := len(matched) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(out) == q.Limit {
+			break
+		}
+		out = append(out, matched[i])
+	}
+	return out, nil
+}
+
+// newID returns a random operation ID.
+func newID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJournal_AppendAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	j, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { j.Close() })
+
+	base := time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC)
+	for i, rec := range []Record{
+		{Tool: "clockify_time_entry_update", Entity: "time_entry", EntityID: "e1", UserID: "u1", Args: map[string]any{"description": "Standup"}},
+		{Tool: "clockify_project_create", Entity: "project", EntityID: "p1", UserID: "u2"},
+		{Tool: "clockify_time_entry_delete", Entity: "time_entry", EntityID: "e1", UserID: "u1"},
+	} {
+		rec.Time = base.Add(time.Duration(i) * time.Hour)
+		if err := j.Append(&rec); err != nil {
+			t.Fatal(err)
+		}
+		if rec.ID == "" {
+			t.Fatal("Append did not assign an ID")
+		}
+	}
+
+	// A line cut short by a crash is skipped.
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"id":"broken","tool":`)
+	f.Close()
+
+	tests := []struct {
+		name  string
+		q     Query
+		tools []string
+	}{
+		{"all, newest first", Query{}, []string{"clockify_time_entry_delete", "clockify_project_create", "clockify_time_entry_update"}},
+		{"tool pattern", Query{Tool: "clockify_time_entry_*"}, []string{"clockify_time_entry_delete", "clockify_time_entry_update"}},
+		{"entity", Query{Entity: "time_entry", EntityID: "e1", Limit: 1}, []string{"clockify_time_entry_delete"}},
+		{"user", Query{UserID: "u2"}, []string{"clockify_project_create"}},
+		{"time window", Query{Since: base.Add(time.Hour), Until: base.Add(2 * time.Hour)}, []string{"clockify_project_create"}},
+		{"text", Query{Text: "STANDUP"}, []string{"clockify_time_entry_update"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, err := j.Query(tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range recs {
+				got = append(got, r.Tool)
+			}
+			if len(got) != len(tt.tools) {
+				t.Fatalf("got %v, want %v", got, tt.tools)
+			}
+			for i := range got {
+				if got[i] != tt.tools[i] {
+					t.Fatalf("got %v, want %v", got, tt.tools)
+				}
+			}
+		})
+	}
+
+	// Reopening finishes the broken line, so new records stay readable.
+	j.Close()
+	if j, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append(&Record{Tool: "clockify_tag_create"}); err != nil {
+		t.Fatal(err)
+	}
+	if recs, _ := j.Query(Query{Tool: "clockify_tag_create"}); len(recs) != 1 {
+		t.Fatalf("record after a broken line was lost: %v", recs)
+	}
+}
@@ -2,52 +2,230 @@ package clockify
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
 const baseURL = "https://api.clockify.me/api/v1"
 const reportsURL = "https://reports.api.clockify.me/v1"
 
+// regionPrefixes maps region shorthands to the subdomain of Clockify's
+// regional servers.
+var regionPrefixes = map[string]string{
+	"eu": "euc1",
+	"us": "use2",
+	"uk": "euw2",
+	"au": "apse2",
+}
+
+// RegionURLs returns the API and reports roots for a Clockify region
+// (eu, us, uk, au). An empty region or "global" selects the default servers.
+func RegionURLs(region string) (api, reports string, err error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region == "" || region == "global" {
+		return baseURL, reportsURL, nil
+	}
+	prefix, ok := regionPrefixes[region]
+	if !ok {
+		return "", "", fmt.Errorf("unknown Clockify region %q (expected global, eu, us, uk or au)", region)
+	}
+	root := "https://" + prefix + ".clockify.me"
+	return root + "/api/v1", root + "/report/v1", nil
+}
+
 type Client struct {
 	apiKey     string
 	httpClient *http.Client
+	retry      RetryPolicy
+	sleep      func(context.Context, time.Duration) error
+	api        *host
+	reports    *host
+}
+
+// host is a Clockify API root together with the rate limiter shared by every
+// request sent to it.
+type host struct {
+	url     string
+	limiter *limiter
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRetryPolicy replaces the default retry policy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithBaseURL points the client at a different main API root, e.g. a regional
+// or self-hosted Clockify instance.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.api.url = strings.TrimRight(u, "/")
+	}
+}
+
+// WithReportsURL points the client at a different reports API root.
+func WithReportsURL(u string) Option {
+	return func(c *Client) {
+		c.reports.url = strings.TrimRight(u, "/")
+	}
+}
+
+// WithRateLimit replaces the default rate limit for the main API.
+func WithRateLimit(rl RateLimit) Option {
+	return func(c *Client) {
+		c.api.limiter = newLimiter(rl)
+	}
+}
+
+// WithReportsRateLimit replaces the default rate limit for the reports API.
+func WithReportsRateLimit(rl RateLimit) Option {
+	return func(c *Client) {
+		c.reports.limiter = newLimiter(rl)
+	}
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...Option) *Client {
+	c := &Client{
 		apiKey: apiKey,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry:   DefaultRetryPolicy(),
+		sleep:   sleepContext,
+		api:     &host{url: baseURL, limiter: newLimiter(DefaultRateLimit())},
+		reports: &host{url: reportsURL, limiter: newLimiter(DefaultReportsRateLimit())},
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-func (c *Client) do(method, endpoint string, body any, result any) error {
-	return c.doWithBase(baseURL, method, endpoint, body, result)
+func (c *Client) do(ctx context.Context, method, endpoint string, body any, result any) error {
+	return c.doWithBase(ctx, c.api, method, endpoint, isIdempotent(method), body, result)
 }
 
-func (c *Client) doReports(method, endpoint string, body any, result any) error {
-	return c.doWithBase(reportsURL, method, endpoint, body, result)
+func (c *Client) doReports(ctx context.Context, method, endpoint string, body any, result any) error {
+	// Report endpoints are read-only queries sent as POST, so retrying them is safe.
+	return c.doWithBase(ctx, c.reports, method, endpoint, true, body, result)
 }
 
-func (c *Client) doWithBase(base, method, endpoint string, body any, result any) error {
-	var bodyReader io.Reader
+// doWithBase sends a request and decodes the response into result. Every
+// attempt first waits for a token from the host's rate limiter. Rate
+// limiting, gateway errors and network failures are retried according to the
+// client's retry policy. Gateway and network errors are only retried when
+// the request is idempotent or the policy allows retrying non-idempotent
+// requests; a rate-limited request never ran, so it is retried regardless.
+func (c *Client) doWithBase(ctx context.Context, h *host, method, endpoint string, idempotent bool, body any, result any) error {
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
+		var err error
+		data, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("marshal request body: %w", err)
 		}
+	}
+
+	maxAttempts := max(c.retry.MaxAttempts, 1)
+	replayable := idempotent || c.retry.RetryNonIdempotent
+
+	for attempt := 1; ; attempt++ {
+		if d := h.limiter.reserve(); d > 0 {
+			if err := c.sleep(ctx, d); err != nil {
+				h.limiter.cancel()
+				return err
+			}
+		}
+
+		respBody, retryAfter, err := c.send(ctx, h.url, method, endpoint, data)
+		if err == nil {
+			if result != nil && len(respBody) > 0 {
+				if err := json.Unmarshal(respBody, result); err != nil {
+					return fmt.Errorf("unmarshal response: %w", err)
+				}
+			}
+			return nil
+		}
+
+		// Once the caller has given up, retrying would only ignore the cancellation.
+		var re *retryableError
+		if !errors.As(err, &re) || (!replayable && !re.rejected) || attempt >= maxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if retryAfter > 0 {
+			if retryAfter > c.retry.MaxDelay {
+				return err
+			}
+			delay = retryAfter
+		}
+		if err := c.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteObserver is told about each response to a request that changes
+// Clockify data, i.e. anything but a GET or a report query, made under a
+// context from WithWriteObserver. path excludes the query; body is the raw
+// response. Retried requests report every response.
+type WriteObserver func(method, path string, status int, body []byte)
+
+type writeObserverKey struct{}
+
+// WithWriteObserver returns a context whose write requests are reported to
+// observe.
+func WithWriteObserver(ctx context.Context, observe WriteObserver) context.Context {
+	return context.WithValue(ctx, writeObserverKey{}, observe)
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryableError marks a failure that may succeed if the request is repeated.
+// rejected means Clockify turned the request away without running it, so
+// repeating it cannot apply it twice.
+type retryableError struct {
+	err      error
+	rejected bool
+}
+
+func (e *retryableError) Error() string { return e.err.Error() }
+func (e *retryableError) Unwrap() error { return e.err }
+
+// send performs a single HTTP round trip. It returns the response body on
+// success, and the server's requested Retry-After delay (if any) alongside
+// retryable failures.
+func (c *Client) send(ctx context.Context, base, method, endpoint string, data []byte) ([]byte, time.Duration, error) {
+	var bodyReader io.Reader
+	if data != nil {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequest(method, base+endpoint, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, base+endpoint, bodyReader)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, 0, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("X-Api-Key", c.apiKey)
@@ -55,30 +233,29 @@ func (c *Client) doWithBase(base, method, endpoint string, body any, result any)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return nil, 0, &retryableError{err: fmt.Errorf("request failed: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return nil, 0, &retryableError{err: fmt.Errorf("read response: %w", err)}
 	}
-
-	if resp.StatusCode == 429 {
-		return fmt.Errorf("clockify rate limit exceeded, try again later")
+	if observe, ok := ctx.Value(writeObserverKey{}).(WriteObserver); ok && method != http.MethodGet && base != c.reports.url {
+		path, _, _ := strings.Cut(endpoint, "?")
+		observe(method, path, resp.StatusCode, respBody)
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("clockify API error (%d): %s", resp.StatusCode, string(respBody))
-	}
-
-	if result != nil && len(respBody) > 0 {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			return fmt.Errorf("unmarshal response: %w", err)
+		apiErr := newAPIError(resp.StatusCode, method, endpoint, respBody)
+		if retryableStatus(resp.StatusCode) {
+			retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+			return nil, retryAfter, &retryableError{err: apiErr, rejected: resp.StatusCode == http.StatusTooManyRequests}
 		}
+		return nil, 0, apiErr
 	}
 
-	return nil
+	return respBody, 0, nil
 }
 
 // --- Workspace ---
@@ -88,9 +265,9 @@ type Workspace struct {
 	Name string `json:"name"`
 }
 
-func (c *Client) GetWorkspaces() ([]Workspace, error) {
+func (c *Client) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
 	var result []Workspace
-	err := c.do("GET", "/workspaces", nil, &result)
+	err := c.do(ctx, "GET", "/workspaces", nil, &result)
 	return result, err
 }
 
@@ -101,20 +278,25 @@ type User struct {
 	Name           string `json:"name"`
 	Email          string `json:"email"`
 	ActiveWorkspace string `json:"activeWorkspace"`
+	Settings       UserSettings `json:"settings"`
 }
 
-func (c *Client) GetCurrentUser() (*User, error) {
+type UserSettings struct {
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
 	var result User
-	err := c.do("GET", "/user", nil, &result)
+	err := c.do(ctx, "GET", "/user", nil, &result)
 	return &result, err
 }
 
-func (c *Client) GetWorkspaceUsers(workspaceID string, page, pageSize int) ([]User, error) {
+func (c *Client) GetWorkspaceUsers(ctx context.Context, workspaceID string, page, pageSize int) ([]User, error) {
 	var result []User
 	q := url.Values{}
 	q.Set("page", fmt.Sprintf("%d", page))
-	q.Set("pageSize", fmt.Sprintf("%d", pageSize))
-	err := c.do("GET", fmt.Sprintf("/workspaces/%s/users?%s", workspaceID, q.Encode()), nil, &result)
+	q.Set("page-size", fmt.Sprintf("%d", pageSize))
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/users?%s", workspaceID, q.Encode()), nil, &result)
 	return result, err
 }
 
@@ -127,6 +309,7 @@ type Project struct {
 	Billable bool   `json:"billable"`
 	Color    string `json:"color,omitempty"`
 	Archived bool   `json:"archived"`
+	Public   bool   `json:"public"`
 }
 
 type CreateProjectRequest struct {
@@ -145,7 +328,7 @@ type UpdateProjectRequest struct {
 	Archived *bool  `json:"archived,omitempty"`
 }
 
-func (c *Client) GetProjects(workspaceID string, archived bool, page, pageSize int) ([]Project, error) {
+func (c *Client) GetProjects(ctx context.Context, workspaceID string, archived bool, page, pageSize int) ([]Project, error) {
 	var result []Project
 	q := url.Values{}
 	if archived {
@@ -154,24 +337,30 @@ func (c *Client) GetProjects(workspaceID string, archived bool, page, pageSize i
 	q.Set("page", fmt.Sprintf("%d", page))
 	q.Set("page-size", fmt.Sprintf("%d", pageSize))
 	endpoint := fmt.Sprintf("/workspaces/%s/projects?%s", workspaceID, q.Encode())
-	err := c.do("GET", endpoint, nil, &result)
+	err := c.do(ctx, "GET", endpoint, nil, &result)
 	return result, err
 }
 
-func (c *Client) CreateProject(workspaceID string, req CreateProjectRequest) (*Project, error) {
+func (c *Client) GetProject(ctx context.Context, workspaceID, projectID string) (*Project, error) {
 	var result Project
-	err := c.do("POST", fmt.Sprintf("/workspaces/%s/projects", workspaceID), req, &result)
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID), nil, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateProject(workspaceID, projectID string, req UpdateProjectRequest) (*Project, error) {
+func (c *Client) CreateProject(ctx context.Context, workspaceID string, req CreateProjectRequest) (*Project, error) {
 	var result Project
-	err := c.do("PUT", fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID), req, &result)
+	err := c.do(ctx, "POST", fmt.Sprintf("/workspaces/%s/projects", workspaceID), req, &result)
 	return &result, err
 }
 
-func (c *Client) DeleteProject(workspaceID, projectID string) error {
-	return c.do("DELETE", fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID), nil, nil)
+func (c *Client) UpdateProject(ctx context.Context, workspaceID, projectID string, req UpdateProjectRequest) (*Project, error) {
+	var result Project
+	err := c.do(ctx, "PUT", fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID), req, &result)
+	return &result, err
+}
+
+func (c *Client) DeleteProject(ctx context.Context, workspaceID, projectID string) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("/workspaces/%s/projects/%s", workspaceID, projectID), nil, nil)
 }
 
 // --- Task ---
@@ -195,29 +384,35 @@ type UpdateTaskRequest struct {
 	Status   string `json:"status,omitempty"`
 }
 
-func (c *Client) GetTasks(workspaceID, projectID string, page, pageSize int) ([]Task, error) {
+func (c *Client) GetTasks(ctx context.Context, workspaceID, projectID string, page, pageSize int) ([]Task, error) {
 	var result []Task
 	q := url.Values{}
 	q.Set("page", fmt.Sprintf("%d", page))
 	q.Set("page-size", fmt.Sprintf("%d", pageSize))
-	err := c.do("GET", fmt.Sprintf("/workspaces/%s/projects/%s/tasks?%s", workspaceID, projectID, q.Encode()), nil, &result)
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/projects/%s/tasks?%s", workspaceID, projectID, q.Encode()), nil, &result)
 	return result, err
 }
 
-func (c *Client) CreateTask(workspaceID, projectID string, req CreateTaskRequest) (*Task, error) {
+func (c *Client) GetTask(ctx context.Context, workspaceID, projectID, taskID string) (*Task, error) {
+	var result Task
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID), nil, &result)
+	return &result, err
+}
+
+func (c *Client) CreateTask(ctx context.Context, workspaceID, projectID string, req CreateTaskRequest) (*Task, error) {
 	var result Task
-	err := c.do("POST", fmt.Sprintf("/workspaces/%s/projects/%s/tasks", workspaceID, projectID), req, &result)
+	err := c.do(ctx, "POST", fmt.Sprintf("/workspaces/%s/projects/%s/tasks", workspaceID, projectID), req, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateTask(workspaceID, projectID, taskID string, req UpdateTaskRequest) (*Task, error) {
+func (c *Client) UpdateTask(ctx context.Context, workspaceID, projectID, taskID string, req UpdateTaskRequest) (*Task, error) {
 	var result Task
-	err := c.do("PUT", fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID), req, &result)
+	err := c.do(ctx, "PUT", fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID), req, &result)
 	return &result, err
 }
 
-func (c *Client) DeleteTask(workspaceID, projectID, taskID string) error {
-	return c.do("DELETE", fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID), nil, nil)
+func (c *Client) DeleteTask(ctx context.Context, workspaceID, projectID, taskID string) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("/workspaces/%s/projects/%s/tasks/%s", workspaceID, projectID, taskID), nil, nil)
 }
 
 // --- Tag ---
@@ -238,29 +433,35 @@ type UpdateTagRequest struct {
 	Archived *bool  `json:"archived,omitempty"`
 }
 
-func (c *Client) GetTags(workspaceID string, page, pageSize int) ([]Tag, error) {
+func (c *Client) GetTags(ctx context.Context, workspaceID string, page, pageSize int) ([]Tag, error) {
 	var result []Tag
 	q := url.Values{}
 	q.Set("page", fmt.Sprintf("%d", page))
 	q.Set("page-size", fmt.Sprintf("%d", pageSize))
-	err := c.do("GET", fmt.Sprintf("/workspaces/%s/tags?%s", workspaceID, q.Encode()), nil, &result)
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/tags?%s", workspaceID, q.Encode()), nil, &result)
 	return result, err
 }
 
-func (c *Client) CreateTag(workspaceID string, req CreateTagRequest) (*Tag, error) {
+func (c *Client) GetTag(ctx context.Context, workspaceID, tagID string) (*Tag, error) {
+	var result Tag
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID), nil, &result)
+	return &result, err
+}
+
+func (c *Client) CreateTag(ctx context.Context, workspaceID string, req CreateTagRequest) (*Tag, error) {
 	var result Tag
-	err := c.do("POST", fmt.Sprintf("/workspaces/%s/tags", workspaceID), req, &result)
+	err := c.do(ctx, "POST", fmt.Sprintf("/workspaces/%s/tags", workspaceID), req, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateTag(workspaceID, tagID string, req UpdateTagRequest) (*Tag, error) {
+func (c *Client) UpdateTag(ctx context.Context, workspaceID, tagID string, req UpdateTagRequest) (*Tag, error) {
 	var result Tag
-	err := c.do("PUT", fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID), req, &result)
+	err := c.do(ctx, "PUT", fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID), req, &result)
 	return &result, err
 }
 
-func (c *Client) DeleteTag(workspaceID, tagID string) error {
-	return c.do("DELETE", fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID), nil, nil)
+func (c *Client) DeleteTag(ctx context.Context, workspaceID, tagID string) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("/workspaces/%s/tags/%s", workspaceID, tagID), nil, nil)
 }
 
 // --- Client (Clockify client entity) ---
@@ -281,29 +482,35 @@ type UpdateClientRequest struct {
 	Archived *bool  `json:"archived,omitempty"`
 }
 
-func (c *Client) GetClients(workspaceID string, page, pageSize int) ([]ClockifyClient, error) {
+func (c *Client) GetClients(ctx context.Context, workspaceID string, page, pageSize int) ([]ClockifyClient, error) {
 	var result []ClockifyClient
 	q := url.Values{}
 	q.Set("page", fmt.Sprintf("%d", page))
 	q.Set("page-size", fmt.Sprintf("%d", pageSize))
-	err := c.do("GET", fmt.Sprintf("/workspaces/%s/clients?%s", workspaceID, q.Encode()), nil, &result)
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/clients?%s", workspaceID, q.Encode()), nil, &result)
 	return result, err
 }
 
-func (c *Client) CreateClient(workspaceID string, req CreateClientRequest) (*ClockifyClient, error) {
+func (c *Client) GetClient(ctx context.Context, workspaceID, clientID string) (*ClockifyClient, error) {
+	var result ClockifyClient
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID), nil, &result)
+	return &result, err
+}
+
+func (c *Client) CreateClient(ctx context.Context, workspaceID string, req CreateClientRequest) (*ClockifyClient, error) {
 	var result ClockifyClient
-	err := c.do("POST", fmt.Sprintf("/workspaces/%s/clients", workspaceID), req, &result)
+	err := c.do(ctx, "POST", fmt.Sprintf("/workspaces/%s/clients", workspaceID), req, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateClient(workspaceID, clientID string, req UpdateClientRequest) (*ClockifyClient, error) {
+func (c *Client) UpdateClient(ctx context.Context, workspaceID, clientID string, req UpdateClientRequest) (*ClockifyClient, error) {
 	var result ClockifyClient
-	err := c.do("PUT", fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID), req, &result)
+	err := c.do(ctx, "PUT", fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID), req, &result)
 	return &result, err
 }
 
-func (c *Client) DeleteClient(workspaceID, clientID string) error {
-	return c.do("DELETE", fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID), nil, nil)
+func (c *Client) DeleteClient(ctx context.Context, workspaceID, clientID string) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("/workspaces/%s/clients/%s", workspaceID, clientID), nil, nil)
 }
 
 // --- Time Entry ---
@@ -345,7 +552,7 @@ type UpdateTimeEntryRequest struct {
 	Billable    bool     `json:"billable"`
 }
 
-func (c *Client) GetTimeEntries(workspaceID, userID string, params url.Values, page, pageSize int) ([]TimeEntry, error) {
+func (c *Client) GetTimeEntries(ctx context.Context, workspaceID, userID string, params url.Values, page, pageSize int) ([]TimeEntry, error) {
 	var result []TimeEntry
 	if params == nil {
 		params = url.Values{}
@@ -353,46 +560,52 @@ func (c *Client) GetTimeEntries(workspaceID, userID string, params url.Values, p
 	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("page-size", fmt.Sprintf("%d", pageSize))
 	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?%s", workspaceID, userID, params.Encode())
-	err := c.do("GET", endpoint, nil, &result)
+	err := c.do(ctx, "GET", endpoint, nil, &result)
 	return result, err
 }
 
-func (c *Client) CreateTimeEntry(workspaceID string, req CreateTimeEntryRequest) (*TimeEntry, error) {
+func (c *Client) GetTimeEntry(ctx context.Context, workspaceID, entryID string) (*TimeEntry, error) {
+	var result TimeEntry
+	err := c.do(ctx, "GET", fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID), nil, &result)
+	return &result, err
+}
+
+func (c *Client) CreateTimeEntry(ctx context.Context, workspaceID string, req CreateTimeEntryRequest) (*TimeEntry, error) {
 	var result TimeEntry
-	err := c.do("POST", fmt.Sprintf("/workspaces/%s/time-entries", workspaceID), req, &result)
+	err := c.do(ctx, "POST", fmt.Sprintf("/workspaces/%s/time-entries", workspaceID), req, &result)
 	return &result, err
 }
 
-func (c *Client) UpdateTimeEntry(workspaceID, entryID string, req UpdateTimeEntryRequest) (*TimeEntry, error) {
+func (c *Client) UpdateTimeEntry(ctx context.Context, workspaceID, entryID string, req UpdateTimeEntryRequest) (*TimeEntry, error) {
 	var result TimeEntry
-	err := c.do("PUT", fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID), req, &result)
+	err := c.do(ctx, "PUT", fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID), req, &result)
 	return &result, err
 }
 
-func (c *Client) DeleteTimeEntry(workspaceID, entryID string) error {
-	return c.do("DELETE", fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID), nil, nil)
+func (c *Client) DeleteTimeEntry(ctx context.Context, workspaceID, entryID string) error {
+	return c.do(ctx, "DELETE", fmt.Sprintf("/workspaces/%s/time-entries/%s", workspaceID, entryID), nil, nil)
 }
 
 // --- Timer ---
 
-func (c *Client) StartTimer(workspaceID string, req CreateTimeEntryRequest) (*TimeEntry, error) {
+func (c *Client) StartTimer(ctx context.Context, workspaceID string, req CreateTimeEntryRequest) (*TimeEntry, error) {
 	// A timer is just a time entry without an end time
 	req.End = ""
-	return c.CreateTimeEntry(workspaceID, req)
+	return c.CreateTimeEntry(ctx, workspaceID, req)
 }
 
-func (c *Client) StopTimer(workspaceID, userID string) (*TimeEntry, error) {
+func (c *Client) StopTimer(ctx context.Context, workspaceID, userID string) (*TimeEntry, error) {
 	var result TimeEntry
 	body := map[string]string{"end": time.Now().UTC().Format("2006-01-02T15:04:05Z")}
-	err := c.do("PATCH", fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID), body, &result)
+	err := c.do(ctx, "PATCH", fmt.Sprintf("/workspaces/%s/user/%s/time-entries", workspaceID, userID), body, &result)
 	return &result, err
 }
 
-func (c *Client) GetRunningTimer(workspaceID, userID string) (*TimeEntry, error) {
+func (c *Client) GetRunningTimer(ctx context.Context, workspaceID, userID string) (*TimeEntry, error) {
 	var result []TimeEntry
 	params := url.Values{"in-progress": {"true"}}
 	endpoint := fmt.Sprintf("/workspaces/%s/user/%s/time-entries?%s", workspaceID, userID, params.Encode())
-	err := c.do("GET", endpoint, nil, &result)
+	err := c.do(ctx, "GET", endpoint, nil, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -410,6 +623,8 @@ type SummaryReportRequest struct {
 	SummaryFilter  *SummaryFilter      `json:"summaryFilter,omitempty"`
 	Users          *ReportUsersFilter  `json:"users,omitempty"`
 	Projects       *ReportProjectFilter `json:"projects,omitempty"`
+	Clients        *ReportClientFilter  `json:"clients,omitempty"`
+	TimeZone       string              `json:"timeZone,omitempty"`
 }
 
 type SummaryFilter struct {
@@ -428,6 +643,12 @@ type ReportProjectFilter struct {
 	Status   string   `json:"status,omitempty"`
 }
 
+type ReportClientFilter struct {
+	IDs      []string `json:"ids,omitempty"`
+	Contains string   `json:"contains,omitempty"`
+	Status   string   `json:"status,omitempty"`
+}
+
 type SummaryReport struct {
 	Totals  []ReportTotal  `json:"totals,omitempty"`
 	GroupOne []ReportGroup `json:"groupOne,omitempty"`
@@ -450,6 +671,8 @@ type DetailedReportRequest struct {
 	DetailedFilter *DetailedFilter     `json:"detailedFilter,omitempty"`
 	Users          *ReportUsersFilter  `json:"users,omitempty"`
 	Projects       *ReportProjectFilter `json:"projects,omitempty"`
+	Clients        *ReportClientFilter  `json:"clients,omitempty"`
+	TimeZone       string              `json:"timeZone,omitempty"`
 	SortColumn     string              `json:"sortColumn,omitempty"`
 	SortOrder      string              `json:"sortOrder,omitempty"`
 	Page           int                 `json:"page,omitempty"`
@@ -480,14 +703,14 @@ type DetailedReportEntry struct {
 	Duration     int64              `json:"duration"`
 }
 
-func (c *Client) GetSummaryReport(workspaceID string, req SummaryReportRequest) (*SummaryReport, error) {
+func (c *Client) GetSummaryReport(ctx context.Context, workspaceID string, req SummaryReportRequest) (*SummaryReport, error) {
 	var result SummaryReport
-	err := c.doReports("POST", fmt.Sprintf("/workspaces/%s/reports/summary", workspaceID), req, &result)
+	err := c.doReports(ctx, "POST", fmt.Sprintf("/workspaces/%s/reports/summary", workspaceID), req, &result)
 	return &result, err
 }
 
-func (c *Client) GetDetailedReport(workspaceID string, req DetailedReportRequest) (*DetailedReport, error) {
+func (c *Client) GetDetailedReport(ctx context.Context, workspaceID string, req DetailedReportRequest) (*DetailedReport, error) {
 	var result DetailedReport
-	err := c.doReports("POST", fmt.Sprintf("/workspaces/%s/reports/detailed", workspaceID), req, &result)
+	err := c.doReports(ctx, "POST", fmt.Sprintf("/workspaces/%s/reports/detailed", workspaceID), req, &result)
 	return &result, err
 }
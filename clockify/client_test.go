@@ -0,0 +1,420 @@
+package clockify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedServer replies with the given status codes in order, repeating the
+// last one once the script runs out. It returns the server and a counter of
+// requests received.
+func scriptedServer(t *testing.T, statuses []int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n >= len(statuses) {
+			n = len(statuses) - 1
+		}
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(statuses[n])
+		if statuses[n] == http.StatusOK {
+			w.Write([]byte(`{"id":"ok"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+// testClient returns a client with a fast retry policy whose sleeps are
+// recorded instead of performed.
+func testClient(policy RetryPolicy) (*Client, *[]time.Duration) {
+	var slept []time.Duration
+	c := NewClient("key", WithRetryPolicy(policy))
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return c, &slept
+}
+
+func fastPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second}
+}
+
+func TestDo_RetriesGatewayErrorsUntilSuccess(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{502, 503, 504, 200}, nil)
+	c, slept := testClient(fastPolicy())
+
+	var out struct{ ID string }
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.ID != "ok" {
+		t.Fatalf("expected decoded response, got %+v", out)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls.Load())
+	}
+	if len(*slept) != 3 {
+		t.Fatalf("expected 3 backoff sleeps, got %v", *slept)
+	}
+}
+
+func TestDo_GivesUpAfterMaxAttempts(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{503}, nil)
+	c, _ := testClient(fastPolicy())
+
+	err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected 503 error, got %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Fatalf("expected 4 attempts, got %d", calls.Load())
+	}
+}
+
+func TestDo_HonorsRetryAfter(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{429, 200}, http.Header{"Retry-After": {"2"}})
+	c, slept := testClient(fastPolicy())
+
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+	if len(*slept) != 1 || (*slept)[0] != 2*time.Second {
+		t.Fatalf("expected a single 2s sleep, got %v", *slept)
+	}
+}
+
+func TestDo_RetryAfterBeyondMaxDelayFailsFast(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{429}, http.Header{"Retry-After": {"3600"}})
+	c, slept := testClient(fastPolicy())
+
+	err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if calls.Load() != 1 || len(*slept) != 0 {
+		t.Fatalf("expected no retries, got %d calls and sleeps %v", calls.Load(), *slept)
+	}
+}
+
+func TestDo_DoesNotRetryNonIdempotentByDefault(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{503, 200}, nil)
+	c, _ := testClient(fastPolicy())
+
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "POST", "/x", false, map[string]string{"a": "b"}, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestDo_RetriesRateLimitedNonIdempotent(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{429, 200}, http.Header{"Retry-After": {"2"}})
+	c, slept := testClient(fastPolicy())
+
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "POST", "/x", false, map[string]string{"a": "b"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 || len(*slept) != 1 || (*slept)[0] != 2*time.Second {
+		t.Fatalf("expected one retry after 2s, got %d calls and sleeps %v", calls.Load(), *slept)
+	}
+}
+
+func TestDo_RetriesNonIdempotentWhenAllowed(t *testing.T) {
+	var bodies []string
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	policy := fastPolicy()
+	policy.RetryNonIdempotent = true
+	c, _ := testClient(policy)
+
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "POST", "/x", false, map[string]string{"a": "b"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Fatalf("expected the same body to be resent, got %q", bodies)
+	}
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{400, 200}, nil)
+	c, _ := testClient(fastPolicy())
+
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestDo_RetriesNetworkErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijack: %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(fastPolicy())
+	if err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestRetryPolicy_BackoffIsBoundedAndGrows(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for retry, ceiling := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for range 20 {
+			d := p.backoff(retry)
+			if d < ceiling/2 || d > ceiling {
+				t.Fatalf("retry %d: backoff %v outside [%v, %v]", retry, d, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDo_CancelledContextAbortsInFlightRequest(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	c, slept := testClient(fastPolicy())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.doWithBase(ctx, &host{url: srv.URL}, "GET", "/x", true, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("request was not aborted promptly (took %v)", elapsed)
+	}
+	if calls.Load() != 1 || len(*slept) != 0 {
+		t.Fatalf("expected no retries after cancellation, got %d calls and sleeps %v", calls.Load(), *slept)
+	}
+}
+
+func TestDo_CancelledContextStopsBackoff(t *testing.T) {
+	srv, calls := scriptedServer(t, []int{503}, nil)
+	c := NewClient("key", WithRetryPolicy(RetryPolicy{MaxAttempts: 4, BaseDelay: time.Hour, MaxDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.doWithBase(ctx, &host{url: srv.URL}, "GET", "/x", true, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestNewClient_BaseURLOptionsTargetFakeServer(t *testing.T) {
+	var gotPath, gotQuery, gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotKey = r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Api-Key")
+		if strings.HasPrefix(r.URL.Path, "/report/") {
+			w.Write([]byte(`{}`))
+			return
+		}
+		w.Write([]byte(`[{"id":"p1","name":"Website"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewClient("secret", WithBaseURL(srv.URL+"/api/v1/"), WithReportsURL(srv.URL+"/report/v1"))
+	projects, err := c.GetProjects(context.Background(), "ws1", false, 2, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Name != "Website" {
+		t.Fatalf("unexpected projects: %+v", projects)
+	}
+	if gotPath != "/api/v1/workspaces/ws1/projects" || gotQuery != "page=2&page-size=10" || gotKey != "secret" {
+		t.Fatalf("unexpected request: path=%q query=%q key=%q", gotPath, gotQuery, gotKey)
+	}
+
+	if _, err := c.GetSummaryReport(context.Background(), "ws1", SummaryReportRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/report/v1/workspaces/ws1/reports/summary" {
+		t.Fatalf("unexpected reports path %q", gotPath)
+	}
+}
+
+func TestRegionURLs(t *testing.T) {
+	tests := []struct {
+		region, api, reports string
+	}{
+		{"", baseURL, reportsURL},
+		{"global", baseURL, reportsURL},
+		{"EU", "https://euc1.clockify.me/api/v1", "https://euc1.clockify.me/report/v1"},
+		{"us", "https://use2.clockify.me/api/v1", "https://use2.clockify.me/report/v1"},
+		{"uk", "https://euw2.clockify.me/api/v1", "https://euw2.clockify.me/report/v1"},
+		{"au", "https://apse2.clockify.me/api/v1", "https://apse2.clockify.me/report/v1"},
+	}
+	for _, tt := range tests {
+		api, reports, err := RegionURLs(tt.region)
+		if err != nil || api != tt.api || reports != tt.reports {
+			t.Errorf("RegionURLs(%q) = %q, %q, %v; want %q, %q", tt.region, api, reports, err, tt.api, tt.reports)
+		}
+	}
+	if _, _, err := RegionURLs("mars"); err == nil {
+		t.Error("expected error for unknown region")
+	}
+}
+
+func TestDo_ReturnsTypedAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Project not found","code":501}`))
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(fastPolicy())
+	err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/workspaces/ws1/projects/p1?hydrated=true", true, nil, nil)
+
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != 501 || apiErr.Message != "Project not found" ||
+		apiErr.Method != "GET" || apiErr.Path != "/workspaces/ws1/projects/p1" {
+		t.Fatalf("unexpected APIError: %+v", apiErr)
+	}
+	if !IsNotFound(err) || IsForbidden(err) || IsRateLimited(err) || IsValidation(err) {
+		t.Fatalf("unexpected classification for %v", err)
+	}
+}
+
+func TestDo_APIErrorKeepsNonJSONBody(t *testing.T) {
+	srv, _ := scriptedServer(t, []int{http.StatusForbidden}, nil)
+	c, _ := testClient(fastPolicy())
+
+	err := c.doWithBase(context.Background(), &host{url: srv.URL}, "DELETE", "/x", true, nil, nil)
+	if !IsForbidden(err) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "DELETE /x") {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
+
+func TestDo_RateLimitedAfterRetriesIsTyped(t *testing.T) {
+	srv, _ := scriptedServer(t, []int{http.StatusTooManyRequests}, nil)
+	c, _ := testClient(fastPolicy())
+
+	err := c.doWithBase(context.Background(), &host{url: srv.URL}, "GET", "/x", true, nil, nil)
+	if !IsRateLimited(err) {
+		t.Fatalf("expected rate limited error, got %v", err)
+	}
+}
+
+func TestWriteObserver_SeesWritesOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(`{"id":"p1","name":"Website"}`))
+		case http.MethodPut:
+			w.Write([]byte(`{"id":"p1","name":"Web"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad"}`))
+		}
+	}))
+	defer srv.Close()
+
+	var seen []string
+	ctx := WithWriteObserver(context.Background(), func(method, path string, status int, body []byte) {
+		seen = append(seen, fmt.Sprintf("%s %s %d %s", method, path, status, body))
+	})
+	c := NewClient("key", WithBaseURL(srv.URL), WithReportsURL(srv.URL+"/reports"))
+	c.GetProject(ctx, "ws1", "p1")
+	c.UpdateProject(ctx, "ws1", "p1", UpdateProjectRequest{Name: "Web"})
+	c.GetSummaryReport(ctx, "ws1", SummaryReportRequest{})
+	c.DeleteProject(ctx, "ws1", "p1")
+
+	want := []string{
+		`PUT /workspaces/ws1/projects/p1 200 {"id":"p1","name":"Web"}`,
+		`DELETE /workspaces/ws1/projects/p1 400 {"message":"bad"}`,
+	}
+	if strings.Join(seen, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("observed:\n%s\nwant:\n%s", strings.Join(seen, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,102 @@
+package clockify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when Clockify answers with a non-2xx status.
+type APIError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Code is Clockify's own error code from the response body, if any.
+	Code int
+	// Message is the human-readable error from the response body.
+	Message string
+	// Method and Path identify the failed request. Path excludes the query.
+	Method string
+	Path   string
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return "clockify rate limit exceeded, try again later"
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code != 0 {
+		return fmt.Sprintf("clockify API error (%d, code %d) on %s %s: %s", e.StatusCode, e.Code, e.Method, e.Path, msg)
+	}
+	return fmt.Sprintf("clockify API error (%d) on %s %s: %s", e.StatusCode, e.Method, e.Path, msg)
+}
+
+// newAPIError builds an APIError from a failed response. Clockify usually
+// returns {"message": ..., "code": ...}; other bodies are kept verbatim.
+func newAPIError(status int, method, endpoint string, body []byte) *APIError {
+	path, _, _ := strings.Cut(endpoint, "?")
+	e := &APIError{StatusCode: status, Method: method, Path: path}
+
+	var payload struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Message != "" {
+		e.Message = payload.Message
+		e.Code = payload.Code
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	return e
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+func hasStatus(err error, codes ...int) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound reports whether err is a Clockify 404.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether Clockify rejected the API key.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether the API key's user lacks permission.
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether Clockify rejected the request with 429.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsValidation reports whether Clockify rejected the request payload.
+func IsValidation(err error) bool {
+	return hasStatus(err, http.StatusBadRequest, http.StatusUnprocessableEntity)
+}
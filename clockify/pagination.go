@@ -0,0 +1,110 @@
+package clockify
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"net/url"
+)
+
+// allPageSize is the page size the All* iterators request.
+const allPageSize = 200
+
+// maxPages bounds how many pages the All* iterators fetch, so a runaway
+// listing cannot exhaust the rate limit budget.
+const maxPages = 50
+
+// ErrPageLimit is yielded by the All* iterators when they stop at maxPages
+// and the page after the last one they fetched is not empty.
+var ErrPageLimit = errors.New("clockify: page limit reached before the end of the listing")
+
+// paginate walks pages starting at 1 until a page comes back empty or shorter
+// than requested. Errors are yielded once and end the iteration. When
+// maxPages full pages came back, the next page is fetched only to tell
+// whether the listing really goes on.
+func paginate[T any](ctx context.Context, fetch func(ctx context.Context, page, pageSize int) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for page := 1; page <= maxPages; page++ {
+			items, err := fetch(ctx, page, allPageSize)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if len(items) < allPageSize {
+				return
+			}
+		}
+		more, err := fetch(ctx, maxPages+1, allPageSize)
+		switch {
+		case err != nil:
+			yield(zero, err)
+		case len(more) > 0:
+			yield(zero, ErrPageLimit)
+		}
+	}
+}
+
+// AllProjects iterates over every project in a workspace.
+func (c *Client) AllProjects(ctx context.Context, workspaceID string, archived bool) iter.Seq2[Project, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]Project, error) {
+		return c.GetProjects(ctx, workspaceID, archived, page, pageSize)
+	})
+}
+
+// AllTasks iterates over every task in a project.
+func (c *Client) AllTasks(ctx context.Context, workspaceID, projectID string) iter.Seq2[Task, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]Task, error) {
+		return c.GetTasks(ctx, workspaceID, projectID, page, pageSize)
+	})
+}
+
+// AllTags iterates over every tag in a workspace.
+func (c *Client) AllTags(ctx context.Context, workspaceID string) iter.Seq2[Tag, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]Tag, error) {
+		return c.GetTags(ctx, workspaceID, page, pageSize)
+	})
+}
+
+// AllClients iterates over every client in a workspace.
+func (c *Client) AllClients(ctx context.Context, workspaceID string) iter.Seq2[ClockifyClient, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]ClockifyClient, error) {
+		return c.GetClients(ctx, workspaceID, page, pageSize)
+	})
+}
+
+// AllWorkspaceUsers iterates over every user in a workspace.
+func (c *Client) AllWorkspaceUsers(ctx context.Context, workspaceID string) iter.Seq2[User, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]User, error) {
+		return c.GetWorkspaceUsers(ctx, workspaceID, page, pageSize)
+	})
+}
+
+// AllTimeEntries iterates over every time entry of a user matching params.
+func (c *Client) AllTimeEntries(ctx context.Context, workspaceID, userID string, params url.Values) iter.Seq2[TimeEntry, error] {
+	return paginate(ctx, func(ctx context.Context, page, pageSize int) ([]TimeEntry, error) {
+		return c.GetTimeEntries(ctx, workspaceID, userID, maps.Clone(params), page, pageSize)
+	})
+}
+
+// Collect drains an iterator into a slice. It reports truncated instead of
+// an error when the iterator stopped at the page limit.
+func Collect[T any](seq iter.Seq2[T, error]) (items []T, truncated bool, err error) {
+	items = []T{}
+	for item, err := range seq {
+		if errors.Is(err, ErrPageLimit) {
+			return items, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, item)
+	}
+	return items, false, nil
+}
@@ -0,0 +1,133 @@
+package clockify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// pagedTagServer serves total tags across pages and counts the requests.
+func pagedTagServer(t *testing.T, total int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("page-size"))
+		tags := []Tag{}
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			tags = append(tags, Tag{ID: fmt.Sprintf("t%d", i)})
+		}
+		json.NewEncoder(w).Encode(tags)
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient("key", WithBaseURL(srv.URL), WithRateLimit(RateLimit{})), &calls
+}
+
+func TestAllTags_WalksUntilShortPage(t *testing.T) {
+	c, calls := pagedTagServer(t, 2*allPageSize+7)
+
+	tags, truncated, err := Collect(c.AllTags(context.Background(), "ws1"))
+	if err != nil || truncated {
+		t.Fatalf("unexpected result: truncated=%v err=%v", truncated, err)
+	}
+	if len(tags) != 2*allPageSize+7 || tags[len(tags)-1].ID != fmt.Sprintf("t%d", 2*allPageSize+6) {
+		t.Fatalf("unexpected tags: %d items", len(tags))
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 page requests, got %d", calls.Load())
+	}
+}
+
+func TestAllTags_StopsOnEmptyPage(t *testing.T) {
+	c, calls := pagedTagServer(t, allPageSize)
+
+	tags, _, err := Collect(c.AllTags(context.Background(), "ws1"))
+	if err != nil || len(tags) != allPageSize {
+		t.Fatalf("unexpected result: %d tags, err=%v", len(tags), err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 page requests, got %d", calls.Load())
+	}
+}
+
+func TestAllTags_StopsAtPageLimit(t *testing.T) {
+	c, calls := pagedTagServer(t, (maxPages+5)*allPageSize)
+
+	tags, truncated, err := Collect(c.AllTags(context.Background(), "ws1"))
+	if err != nil || !truncated {
+		t.Fatalf("expected truncated result, got truncated=%v err=%v", truncated, err)
+	}
+	// One request past the limit confirms there is more.
+	if len(tags) != maxPages*allPageSize || int(calls.Load()) != maxPages+1 {
+		t.Fatalf("expected %d pages, got %d requests and %d tags", maxPages, calls.Load(), len(tags))
+	}
+}
+
+func TestAllTags_FullLastPageAtLimitIsNotTruncated(t *testing.T) {
+	c, calls := pagedTagServer(t, maxPages*allPageSize)
+
+	tags, truncated, err := Collect(c.AllTags(context.Background(), "ws1"))
+	if err != nil || truncated {
+		t.Fatalf("expected the whole listing, got truncated=%v err=%v", truncated, err)
+	}
+	if len(tags) != maxPages*allPageSize || int(calls.Load()) != maxPages+1 {
+		t.Fatalf("expected %d requests, got %d requests and %d tags", maxPages+1, calls.Load(), len(tags))
+	}
+}
+
+func TestAllTags_EarlyBreakStopsFetching(t *testing.T) {
+	c, calls := pagedTagServer(t, 3*allPageSize)
+
+	n := 0
+	for _, err := range c.AllTags(context.Background(), "ws1") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n++; n == 3 {
+			break
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 page request, got %d", calls.Load())
+	}
+}
+
+func TestAllTags_YieldsErrors(t *testing.T) {
+	srv, _ := scriptedServer(t, []int{http.StatusForbidden}, nil)
+	c := NewClient("key", WithBaseURL(srv.URL))
+
+	_, _, err := Collect(c.AllTags(context.Background(), "ws1"))
+	if !IsForbidden(err) {
+		t.Fatalf("expected forbidden error, got %v", err)
+	}
+}
+
+func TestAllWorkspaceUsers_SendsPageSize(t *testing.T) {
+	const total = 60
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		// Like Clockify, fall back to 50 rows when page-size is missing.
+		size, err := strconv.Atoi(r.URL.Query().Get("page-size"))
+		if err != nil {
+			size = 50
+		}
+		users := []User{}
+		for i := (page - 1) * size; i < page*size && i < total; i++ {
+			users = append(users, User{ID: fmt.Sprintf("u%d", i)})
+		}
+		json.NewEncoder(w).Encode(users)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient("key", WithBaseURL(srv.URL), WithRateLimit(RateLimit{}))
+
+	users, truncated, err := Collect(c.AllWorkspaceUsers(context.Background(), "ws1"))
+	if err != nil || truncated || len(users) != total {
+		t.Fatalf("expected %d users, got %d (truncated=%v, err=%v)", total, len(users), truncated, err)
+	}
+}
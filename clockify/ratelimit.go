@@ -0,0 +1,95 @@
+package clockify
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimit configures a client-side token bucket.
+type RateLimit struct {
+	// RequestsPerSecond is the sustained request rate. Zero or negative
+	// disables limiting.
+	RequestsPerSecond float64
+	// Burst is the number of requests that may be sent back to back before
+	// the sustained rate applies.
+	Burst int
+}
+
+// DefaultRateLimit returns the limit applied to the main Clockify API. It stays
+// well below Clockify's per-key budget so several concurrent tool calls fit.
+func DefaultRateLimit() RateLimit {
+	return RateLimit{RequestsPerSecond: 10, Burst: 10}
+}
+
+// DefaultReportsRateLimit returns the limit applied to the reports API, which
+// has its own, lower budget.
+func DefaultReportsRateLimit() RateLimit {
+	return RateLimit{RequestsPerSecond: 5, Burst: 5}
+}
+
+// limiter is a token bucket shared by every request sent to one host.
+// Callers that find the bucket empty reserve a future token and sleep until
+// it becomes available, so waiting callers are served in arrival order.
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newLimiter(rl RateLimit) *limiter {
+	burst := float64(rl.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		rate:   rl.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		now:    time.Now,
+	}
+}
+
+// reserve takes one token and returns how long the caller has to wait before
+// sending its request.
+func (l *limiter) reserve() time.Duration {
+	if l == nil || l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel gives back a token taken by reserve when the caller stopped waiting
+// for it, so later callers do not queue behind a request that was never sent.
+func (l *limiter) cancel() {
+	if l == nil || l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+}
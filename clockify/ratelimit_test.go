@@ -0,0 +1,146 @@
+package clockify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced time source for limiter tests.
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestLimiter(rl RateLimit) (*limiter, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := newLimiter(rl)
+	l.now = clock.now
+	return l, clock
+}
+
+func TestLimiter_AllowsBurstThenQueues(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{RequestsPerSecond: 2, Burst: 3})
+
+	for i := range 3 {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d within burst waited %v", i, d)
+		}
+	}
+	// Each further request queues behind the previous reservation.
+	for i, want := range []time.Duration{500 * time.Millisecond, time.Second, 1500 * time.Millisecond} {
+		if d := l.reserve(); d != want {
+			t.Fatalf("queued request %d: wait %v, want %v", i, d, want)
+		}
+	}
+}
+
+func TestLimiter_RefillsOverTimeUpToBurst(t *testing.T) {
+	l, clock := newTestLimiter(RateLimit{RequestsPerSecond: 1, Burst: 2})
+
+	l.reserve()
+	l.reserve()
+	clock.advance(time.Hour)
+
+	for i := range 2 {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("request %d after refill waited %v", i, d)
+		}
+	}
+	if d := l.reserve(); d != time.Second {
+		t.Fatalf("expected refill to be capped at burst, got wait %v", d)
+	}
+}
+
+func TestLimiter_CancelGivesTheTokenBack(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})
+
+	l.reserve()
+	if d := l.reserve(); d != time.Second {
+		t.Fatalf("expected to queue for 1s, got %v", d)
+	}
+	l.cancel()
+	// The next caller takes the cancelled reservation's place in the queue.
+	if d := l.reserve(); d != time.Second {
+		t.Fatalf("expected to queue for 1s after a cancel, got %v", d)
+	}
+}
+
+func TestLimiter_DisabledNeverWaits(t *testing.T) {
+	l, _ := newTestLimiter(RateLimit{})
+	for range 100 {
+		if d := l.reserve(); d != 0 {
+			t.Fatalf("disabled limiter waited %v", d)
+		}
+	}
+	var nilLimiter *limiter
+	if d := nilLimiter.reserve(); d != 0 {
+		t.Fatalf("nil limiter waited %v", d)
+	}
+}
+
+func TestClient_SeparateBucketsForAPIAndReports(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	c, slept := testClient(fastPolicy())
+	c.api = &host{url: srv.URL, limiter: newLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})}
+	c.reports = &host{url: srv.URL, limiter: newLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})}
+
+	// One request to each host fits in its own bucket.
+	if err := c.do(ctx, "GET", "/a", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.doReports(ctx, "POST", "/b", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 0 {
+		t.Fatalf("expected no waiting, got %v", *slept)
+	}
+
+	// A second API request has to wait for its bucket to refill.
+	if err := c.do(ctx, "GET", "/a", nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*slept) != 1 || (*slept)[0] <= 0 {
+		t.Fatalf("expected one limiter wait, got %v", *slept)
+	}
+}
+
+func TestClient_CancelledWaitReturnsItsToken(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(fastPolicy())
+	l, _ := newTestLimiter(RateLimit{RequestsPerSecond: 1, Burst: 1})
+	h := &host{url: srv.URL, limiter: l}
+	ctx := context.Background()
+	if err := c.doWithBase(ctx, h, "GET", "/a", true, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The caller gives up while waiting for its token.
+	c.sleep = func(context.Context, time.Duration) error { return context.Canceled }
+	for range 3 {
+		if err := c.doWithBase(ctx, h, "GET", "/a", true, nil, nil); err != context.Canceled {
+			t.Fatalf("expected the cancellation, got %v", err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("cancelled requests were sent: %d calls", calls)
+	}
+	if d := l.reserve(); d != time.Second {
+		t.Fatalf("cancelled waits kept their tokens: next wait %v, want 1s", d)
+	}
+}
@@ -5,11 +5,78 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
 	APIKey      string `json:"api_key"`
 	WorkspaceID string `json:"workspace_id,omitempty"`
+
+	// Region selects a regional Clockify server (eu, us, uk, au). Explicit
+	// base URLs take precedence over it.
+	Region         string `json:"region,omitempty"`
+	APIBaseURL     string `json:"api_base_url,omitempty"`
+	ReportsBaseURL string `json:"reports_base_url,omitempty"`
+
+	// Retry settings for Clockify API requests. Zero values use the client defaults.
+	RetryMaxAttempts   int  `json:"retry_max_attempts,omitempty"`
+	RetryBaseDelayMs   int  `json:"retry_base_delay_ms,omitempty"`
+	RetryMaxDelayMs    int  `json:"retry_max_delay_ms,omitempty"`
+	RetryNonIdempotent bool `json:"retry_non_idempotent,omitempty"`
+
+	// Client-side rate limits. Zero values use the client defaults; a negative
+	// rate disables limiting.
+	RateLimitRPS          float64 `json:"rate_limit_rps,omitempty"`
+	RateLimitBurst        int     `json:"rate_limit_burst,omitempty"`
+	ReportsRateLimitRPS   float64 `json:"reports_rate_limit_rps,omitempty"`
+	ReportsRateLimitBurst int     `json:"reports_rate_limit_burst,omitempty"`
+
+	// CacheTTLSeconds controls how long user, workspace, project, tag and
+	// client metadata is cached. Zero uses the default; negative disables it.
+	CacheTTLSeconds int `json:"cache_ttl_seconds,omitempty"`
+
+	// Timezone is an IANA zone name (e.g. Europe/Prague) used for dates and
+	// day boundaries. Empty uses the timezone from the Clockify user settings.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Transport is the MCP transport: stdio (default), http or sse.
+	// ListenAddr is the address the http and sse transports listen on.
+	Transport  string `json:"transport,omitempty"`
+	ListenAddr string `json:"listen_addr,omitempty"`
+
+	// ServerKeyFallback lets http and sse callers that send no API key of
+	// their own use APIKey. Off by default, so such calls are refused.
+	ServerKeyFallback bool `json:"server_key_fallback,omitempty"`
+
+	// TimerPollSeconds is how often the running timer is polled for
+	// resource subscribers. Zero uses the default.
+	TimerPollSeconds int `json:"timer_poll_seconds,omitempty"`
+
+	// StructuredContent is off (default), on or auto: whether JSON tool
+	// results also carry structuredContent. auto leaves it out for clients
+	// known to reject it.
+	StructuredContent string `json:"structured_content,omitempty"`
+
+	// ReadOnly hides every tool that changes Clockify data. EnabledTools
+	// and DisabledTools are glob patterns over tool names; with EnabledTools
+	// set only matching tools are exposed, and DisabledTools removes more.
+	ReadOnly      bool     `json:"read_only,omitempty"`
+	EnabledTools  []string `json:"enabled_tools,omitempty"`
+	DisabledTools []string `json:"disabled_tools,omitempty"`
+
+	// DryRun makes every tool that changes Clockify data report the request
+	// it would send instead of sending it.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// AuditLog is the JSON-lines file every change to Clockify is recorded
+	// in. Empty disables the audit log.
+	AuditLog string `json:"audit_log,omitempty"`
+
+	// ConfirmDeletes names the delete tools that need a confirmation token
+	// from a preview call. Unset means project and client deletion; an empty
+	// list, or "none" in the env variable, turns confirmation off.
+	ConfirmDeletes []string `json:"confirm_deletes,omitempty"`
 }
 
 const configDir = "ticktock-mcp"
@@ -18,6 +85,23 @@ const configFile = "config.json"
 // Load reads configuration from environment variables with fallback to config file.
 // Priority: CLOCKIFY_API_KEY env > config file api_key
 // Optional: CLOCKIFY_WORKSPACE_ID env > config file workspace_id
+// Optional: CLOCKIFY_REGION, CLOCKIFY_API_BASE_URL, CLOCKIFY_REPORTS_BASE_URL env > config file
+// Optional: CLOCKIFY_RETRY_MAX_ATTEMPTS, CLOCKIFY_RETRY_BASE_DELAY_MS,
+// CLOCKIFY_RETRY_MAX_DELAY_MS, CLOCKIFY_RETRY_NON_IDEMPOTENT env > config file
+// Optional: CLOCKIFY_RATE_LIMIT_RPS, CLOCKIFY_RATE_LIMIT_BURST,
+// CLOCKIFY_REPORTS_RATE_LIMIT_RPS, CLOCKIFY_REPORTS_RATE_LIMIT_BURST env > config file
+// Optional: CLOCKIFY_CACHE_TTL_SECONDS env > config file
+// Optional: CLOCKIFY_TIMEZONE env > config file timezone
+// Optional: CLOCKIFY_TRANSPORT, CLOCKIFY_LISTEN_ADDR env > config file
+// Optional: CLOCKIFY_SERVER_KEY_FALLBACK env > config file server_key_fallback
+// Optional: CLOCKIFY_TIMER_POLL_SECONDS env > config file
+// Optional: CLOCKIFY_STRUCTURED_CONTENT env > config file structured_content
+// Optional: CLOCKIFY_READ_ONLY, CLOCKIFY_ENABLED_TOOLS, CLOCKIFY_DISABLED_TOOLS
+// (comma-separated) env > config file
+// Optional: CLOCKIFY_DRY_RUN env > config file dry_run
+// Optional: CLOCKIFY_AUDIT_LOG env > config file audit_log
+// Optional: CLOCKIFY_CONFIRM_DELETES (comma-separated) env > config file
+// confirm_deletes
 func Load() (*Config, error) {
 	cfg := &Config{}
 
@@ -33,14 +117,148 @@ func Load() (*Config, error) {
 	if wsID := os.Getenv("CLOCKIFY_WORKSPACE_ID"); wsID != "" {
 		cfg.WorkspaceID = wsID
 	}
+	if region := os.Getenv("CLOCKIFY_REGION"); region != "" {
+		cfg.Region = region
+	}
+	if u := os.Getenv("CLOCKIFY_API_BASE_URL"); u != "" {
+		cfg.APIBaseURL = u
+	}
+	if u := os.Getenv("CLOCKIFY_REPORTS_BASE_URL"); u != "" {
+		cfg.ReportsBaseURL = u
+	}
+	if err := envInt("CLOCKIFY_RETRY_MAX_ATTEMPTS", &cfg.RetryMaxAttempts); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_RETRY_BASE_DELAY_MS", &cfg.RetryBaseDelayMs); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_RETRY_MAX_DELAY_MS", &cfg.RetryMaxDelayMs); err != nil {
+		return nil, err
+	}
+	if err := envBool("CLOCKIFY_RETRY_NON_IDEMPOTENT", &cfg.RetryNonIdempotent); err != nil {
+		return nil, err
+	}
+	if err := envFloat("CLOCKIFY_RATE_LIMIT_RPS", &cfg.RateLimitRPS); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_RATE_LIMIT_BURST", &cfg.RateLimitBurst); err != nil {
+		return nil, err
+	}
+	if err := envFloat("CLOCKIFY_REPORTS_RATE_LIMIT_RPS", &cfg.ReportsRateLimitRPS); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_REPORTS_RATE_LIMIT_BURST", &cfg.ReportsRateLimitBurst); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_CACHE_TTL_SECONDS", &cfg.CacheTTLSeconds); err != nil {
+		return nil, err
+	}
 
-	if cfg.APIKey == "" {
-		return nil, fmt.Errorf("CLOCKIFY_API_KEY not set (use env variable or ~/.config/%s/%s)", configDir, configFile)
+	if tz := os.Getenv("CLOCKIFY_TIMEZONE"); tz != "" {
+		cfg.Timezone = tz
+	}
+	if t := os.Getenv("CLOCKIFY_TRANSPORT"); t != "" {
+		cfg.Transport = t
+	}
+	if addr := os.Getenv("CLOCKIFY_LISTEN_ADDR"); addr != "" {
+		cfg.ListenAddr = addr
+	}
+	if err := envBool("CLOCKIFY_SERVER_KEY_FALLBACK", &cfg.ServerKeyFallback); err != nil {
+		return nil, err
+	}
+	if err := envInt("CLOCKIFY_TIMER_POLL_SECONDS", &cfg.TimerPollSeconds); err != nil {
+		return nil, err
+	}
+	if sc := os.Getenv("CLOCKIFY_STRUCTURED_CONTENT"); sc != "" {
+		cfg.StructuredContent = sc
+	}
+	if err := envBool("CLOCKIFY_READ_ONLY", &cfg.ReadOnly); err != nil {
+		return nil, err
+	}
+	envList("CLOCKIFY_ENABLED_TOOLS", &cfg.EnabledTools)
+	envList("CLOCKIFY_DISABLED_TOOLS", &cfg.DisabledTools)
+	if err := envBool("CLOCKIFY_DRY_RUN", &cfg.DryRun); err != nil {
+		return nil, err
+	}
+	if p := os.Getenv("CLOCKIFY_AUDIT_LOG"); p != "" {
+		cfg.AuditLog = p
+	}
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("CLOCKIFY_CONFIRM_DELETES")), "none") {
+		cfg.ConfirmDeletes = []string{}
+	} else {
+		envList("CLOCKIFY_CONFIRM_DELETES", &cfg.ConfirmDeletes)
 	}
 
 	return cfg, nil
 }
 
+// CheckAPIKey reports an error if no API key is configured. The HTTP
+// transports can run without one, taking each caller's key instead.
+func (c *Config) CheckAPIKey() error {
+	if c.APIKey == "" {
+		return fmt.Errorf("CLOCKIFY_API_KEY not set (use env variable or ~/.config/%s/%s)", configDir, configFile)
+	}
+	return nil
+}
+
+// envInt overrides dst with the named env variable if it is set.
+func envInt(name string, dst *int) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = n
+	return nil
+}
+
+// envFloat overrides dst with the named env variable if it is set.
+func envFloat(name string, dst *float64) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = f
+	return nil
+}
+
+// envBool overrides dst with the named env variable if it is set.
+func envBool(name string, dst *bool) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	*dst = b
+	return nil
+}
+
+// envList overrides dst with the comma-separated items of the named env
+// variable if it is set.
+func envList(name string, dst *[]string) {
+	v := os.Getenv(name)
+	if v == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
 func loadFromFile() (*Config, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
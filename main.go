@@ -1,49 +1,206 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // the scratch image ships no zoneinfo
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/audit"
 	"github.com/tedyno/ticktock-mcp/clockify"
 	"github.com/tedyno/ticktock-mcp/config"
 	"github.com/tedyno/ticktock-mcp/tools"
 )
 
+// defaultListenAddr is where the http and sse transports listen by default.
+const defaultListenAddr = ":8080"
+
 func main() {
+	transport := flag.String("transport", "", "MCP transport: stdio, http or sse (default stdio)")
+	listenAddr := flag.String("listen", "", "listen address for the http and sse transports (default "+defaultListenAddr+")")
+	flag.Parse()
+
 	cfg, err := config.Load()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if *transport != "" {
+		cfg.Transport = *transport
+	}
+	if *listenAddr != "" {
+		cfg.ListenAddr = *listenAddr
+	}
+	if cfg.ListenAddr == "" {
+		cfg.ListenAddr = defaultListenAddr
+	}
+	if err := checkTransport(cfg.Transport); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	client := clockify.NewClient(cfg.APIKey)
+	// Over HTTP every caller may bring their own Clockify key, so the
+	// server's key is optional there.
+	multiTenant := cfg.Transport == transportHTTP || cfg.Transport == transportSSE
+	if !multiTenant {
+		if err := cfg.CheckAPIKey(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts, err := clientOptions(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Resolve default workspace ID
-	workspaceID := cfg.WorkspaceID
-	if workspaceID == "" {
-		workspaces, err := client.GetWorkspaces()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error fetching workspaces: %v\n", err)
+	var location *time.Location
+	if cfg.Timezone != "" {
+		if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid timezone %q: %v\n", cfg.Timezone, err)
 			os.Exit(1)
 		}
-		if len(workspaces) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: no workspaces found for this API key\n")
+	}
+
+	structured, err := tools.ParseStructuredMode(cfg.StructuredContent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, patterns := range [][]string{cfg.EnabledTools, cfg.DisabledTools} {
+		if err := tools.CheckToolPatterns(patterns); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		workspaceID = workspaces[0].ID
+	}
+
+	if err := tools.CheckConfirmDeletes(cfg.ConfirmDeletes); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var journal *audit.Journal
+	if cfg.AuditLog != "" {
+		if journal, err = audit.Open(cfg.AuditLog); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer journal.Close()
+	}
+
+	var client *clockify.Client
+	var workspaceID string
+	if cfg.APIKey != "" {
+		client = clockify.NewClient(cfg.APIKey, opts...)
+
+		// Resolve default workspace ID
+		workspaceID = cfg.WorkspaceID
+		if workspaceID == "" {
+			workspaces, err := client.GetWorkspaces(context.Background())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error fetching workspaces: %v\n", err)
+				os.Exit(1)
+			}
+			if len(workspaces) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: no workspaces found for this API key\n")
+				os.Exit(1)
+			}
+			workspaceID = workspaces[0].ID
+		}
 	}
 
 	s := server.NewMCPServer(
 		"ticktock-mcp",
 		"1.0.0",
 		server.WithToolCapabilities(false),
+		// The sse transport cannot answer subscription requests itself.
+		server.WithResourceCapabilities(cfg.Transport != transportSSE, false),
+		server.WithPromptCapabilities(false),
 	)
 
-	tools.RegisterAll(s, client, workspaceID)
+	toolOpts := tools.Options{
+		CacheTTL:          time.Duration(cfg.CacheTTLSeconds) * time.Second,
+		Location:          location,
+		TimerPollInterval: time.Duration(cfg.TimerPollSeconds) * time.Second,
+		StructuredContent: structured,
+		ReadOnly:          cfg.ReadOnly,
+		DryRun:            cfg.DryRun,
+		Journal:           journal,
+		ConfirmDeletes:    cfg.ConfirmDeletes,
+		EnabledTools:      cfg.EnabledTools,
+		DisabledTools:     cfg.DisabledTools,
+	}
+	if multiTenant {
+		toolOpts.NewClient = func(apiKey string) *clockify.Client {
+			return clockify.NewClient(apiKey, opts...)
+		}
+		toolOpts.ServerKeyFallback = cfg.ServerKeyFallback
+	}
+	subs := tools.RegisterAll(s, client, workspaceID, toolOpts)
+	logExposedTools(s)
 
-	if err := server.ServeStdio(s); err != nil {
+	if err := serve(s, subs, cfg.Transport, cfg.ListenAddr); err != nil {
 		log.Fatalf("Server error: %v", err)
 	}
 }
+
+// logExposedTools lists the tools left after read-only mode and the tool
+// filters, so operators can check what agents can call.
+func logExposedTools(s *server.MCPServer) {
+	names := slices.Sorted(maps.Keys(s.ListTools()))
+	log.Printf("Exposing %d tools: %s", len(names), strings.Join(names, ", "))
+}
+
+// clientOptions translates config settings into Clockify client options.
+func clientOptions(cfg *config.Config) ([]clockify.Option, error) {
+	apiURL, reportsURL, err := clockify.RegionURLs(cfg.Region)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.APIBaseURL != "" {
+		apiURL = cfg.APIBaseURL
+	}
+	if cfg.ReportsBaseURL != "" {
+		reportsURL = cfg.ReportsBaseURL
+	}
+
+	retry := clockify.DefaultRetryPolicy()
+	if cfg.RetryMaxAttempts > 0 {
+		retry.MaxAttempts = cfg.RetryMaxAttempts
+	}
+	if cfg.RetryBaseDelayMs > 0 {
+		retry.BaseDelay = time.Duration(cfg.RetryBaseDelayMs) * time.Millisecond
+	}
+	if cfg.RetryMaxDelayMs > 0 {
+		retry.MaxDelay = time.Duration(cfg.RetryMaxDelayMs) * time.Millisecond
+	}
+	retry.RetryNonIdempotent = cfg.RetryNonIdempotent
+
+	return []clockify.Option{
+		clockify.WithBaseURL(apiURL),
+		clockify.WithReportsURL(reportsURL),
+		clockify.WithRetryPolicy(retry),
+		clockify.WithRateLimit(rateLimit(clockify.DefaultRateLimit(), cfg.RateLimitRPS, cfg.RateLimitBurst)),
+		clockify.WithReportsRateLimit(rateLimit(clockify.DefaultReportsRateLimit(), cfg.ReportsRateLimitRPS, cfg.ReportsRateLimitBurst)),
+	}, nil
+}
+
+// rateLimit applies configured overrides to a default rate limit.
+func rateLimit(rl clockify.RateLimit, rps float64, burst int) clockify.RateLimit {
+	if rps != 0 {
+		rl.RequestsPerSecond = rps
+	}
+	if burst > 0 {
+		rl.Burst = burst
+	}
+	return rl
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/tools"
+)
+
+const initializeRequest = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`
+
+const userCurrentRequest = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"clockify_user_current","arguments":{}}}`
+
+const unsubscribeRequest = `{"jsonrpc":"2.0","id":2,"method":"resources/unsubscribe","params":{"uri":"clockify://timer/current"}}`
+
+// newTestServer returns an MCP server with the Clockify tools registered and
+// no server API key.
+func newTestServer() (*server.MCPServer, *tools.Subscriptions) {
+	s := server.NewMCPServer("ticktock-mcp", "test", server.WithResourceCapabilities(true, false))
+	return s, tools.RegisterAll(s, nil, "", tools.Options{})
+}
+
+func TestHTTPTransport_ServesMCPAndHealthz(t *testing.T) {
+	s, subs := newTestServer()
+	srv, _ := newHTTPServer(s, subs, transportHTTP, "")
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz returned %d", resp.StatusCode)
+	}
+
+	resp, err = http.Post(ts.URL+"/mcp", "application/json", strings.NewReader(initializeRequest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"serverInfo"`) {
+		t.Fatalf("initialize returned %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestHTTPTransport_AnswersSubscriptionRequests(t *testing.T) {
+	s, subs := newTestServer()
+	srv, _ := newHTTPServer(s, subs, transportHTTP, "")
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(unsubscribeRequest))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(server.HeaderKeySessionID, "session-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"id":2,"result":{}`) {
+		t.Fatalf("unsubscribe returned %d: %s", resp.StatusCode, body)
+	}
+}
+
+func TestStdioTransport_AnswersSubscriptionRequests(t *testing.T) {
+	s, subs := newTestServer()
+	in := strings.NewReader(initializeRequest + "\n" + unsubscribeRequest + "\n")
+	var out strings.Builder
+	if err := serveStdio(context.Background(), s, subs, in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"serverInfo"`) || !strings.Contains(lines[1], `"id":2,"result":{}`) {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestSSETransport_ServesHealthz(t *testing.T) {
+	s, subs := newTestServer()
+	srv, _ := newHTTPServer(s, subs, transportSSE, "")
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("healthz returned %d", resp.StatusCode)
+	}
+}
+
+func TestServeHTTP_ShutsDownWhenCancelled(t *testing.T) {
+	s, subs := newTestServer()
+	srv, tr := newHTTPServer(s, subs, transportHTTP, "127.0.0.1:0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTP(ctx, srv, tr) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(shutdownTimeout):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestCheckTransport(t *testing.T) {
+	for _, ok := range []string{"", "stdio", "http", "sse"} {
+		if err := checkTransport(ok); err != nil {
+			t.Errorf("checkTransport(%q) = %v", ok, err)
+		}
+	}
+	if err := checkTransport("websocket"); err == nil {
+		t.Error("expected error for unknown transport")
+	}
+}
+
+func TestCallerAPIKey(t *testing.T) {
+	tests := []struct {
+		header, value string
+		want          bool
+	}{
+		{"X-Api-Key", "k1", true},
+		{"Authorization", "Bearer k1", true},
+		{"Authorization", "bearer  k1 ", true},
+		{"Authorization", "Basic azE6", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+		if tt.header != "" {
+			r.Header.Set(tt.header, tt.value)
+		}
+		ctx := callerAPIKey(context.Background(), r)
+		if got := ctx != context.Background(); got != tt.want {
+			t.Errorf("%s: %q attached key = %v, want %v", tt.header, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPTransport_RefusesKeylessCallers(t *testing.T) {
+	clockifyAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("keyless call reached Clockify: %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}))
+	defer clockifyAPI.Close()
+	newClient := func(apiKey string) *clockify.Client {
+		return clockify.NewClient(apiKey, clockify.WithBaseURL(clockifyAPI.URL))
+	}
+
+	s := server.NewMCPServer("ticktock-mcp", "test")
+	subs := tools.RegisterAll(s, newClient("server-key"), "ws1", tools.Options{NewClient: newClient})
+	srv, _ := newHTTPServer(s, subs, transportHTTP, "")
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/mcp", "application/json", strings.NewReader(initializeRequest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(userCurrentRequest))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(server.HeaderKeySessionID, resp.Header.Get(server.HeaderKeySessionID))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"isError":true`) || !strings.Contains(string(body), "API key is required") {
+		t.Fatalf("keyless call was not refused: %s", body)
+	}
+}
+
+func TestServeHTTP_ClosesOpenStreamsOnShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s, subs := newTestServer()
+	srv, tr := newHTTPServer(s, subs, transportHTTP, addr)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTP(ctx, srv, tr) }()
+
+	// A client of its own keeps no idle connections that would make
+	// Shutdown wait out the grace period net/http gives new connections.
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	var resp *http.Response
+	for range 50 {
+		if resp, err = client.Post("http://"+addr+"/mcp", "application/json", strings.NewReader(initializeRequest)); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// Open the notification stream of the session.
+	req, _ := http.NewRequest(http.MethodGet, "http://"+addr+"/mcp", nil)
+	req.Header.Set(server.HeaderKeySessionID, resp.Header.Get(server.HeaderKeySessionID))
+	stream, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("server waited for the open stream")
+	}
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/tools"
+)
+
+// shutdownTimeout bounds how long open sessions may take to finish after
+// SIGTERM before the listener is closed anyway.
+const shutdownTimeout = 10 * time.Second
+
+// Transport names accepted by --transport.
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+	transportSSE   = "sse"
+)
+
+// stdioSessionID is the ID mcp-go's stdio transport registers its single
+// session under.
+const stdioSessionID = "stdio"
+
+// httpTransport is an mcp-go HTTP transport bound to our http.Server.
+type httpTransport interface {
+	http.Handler
+	Shutdown(ctx context.Context) error
+}
+
+// checkTransport validates a --transport value.
+func checkTransport(transport string) error {
+	switch transport {
+	case "", transportStdio, transportHTTP, transportSSE:
+		return nil
+	default:
+		return fmt.Errorf("unknown transport %q (use stdio, http or sse)", transport)
+	}
+}
+
+// serve runs s over the given transport until stdin closes (stdio) or the
+// process receives SIGINT or SIGTERM. Resource subscriptions are answered by
+// subs on stdio and streamable HTTP; the sse transport does not support them.
+func serve(s *server.MCPServer, subs *tools.Subscriptions, transport, addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch transport {
+	case "", transportStdio:
+		go subs.Run(ctx)
+		return serveStdio(ctx, s, subs, os.Stdin, os.Stdout)
+	case transportHTTP, transportSSE:
+		if transport == transportHTTP {
+			go subs.Run(ctx)
+		}
+		srv, t := newHTTPServer(s, subs, transport, addr)
+		return serveHTTP(ctx, srv, t)
+	default:
+		return checkTransport(transport)
+	}
+}
+
+// serveStdio runs s over in and out, answering subscription requests read
+// from in before they reach the stdio transport.
+func serveStdio(ctx context.Context, s *server.MCPServer, subs *tools.Subscriptions, in io.Reader, out io.Writer) error {
+	w := &lockedWriter{w: out}
+	r := &subscriptionReader{ctx: ctx, in: bufio.NewReader(in), out: w, subs: subs}
+	return server.NewStdioServer(s).Listen(ctx, r, w)
+}
+
+// lockedWriter serializes writes, so responses written by subscriptionReader
+// do not interleave with the stdio transport's own output.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
+
+// subscriptionReader passes newline-delimited JSON-RPC messages through,
+// except subscription requests, which it answers on out itself.
+type subscriptionReader struct {
+	ctx     context.Context
+	in      *bufio.Reader
+	out     io.Writer
+	subs    *tools.Subscriptions
+	pending []byte
+}
+
+func (r *subscriptionReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		line, err := r.in.ReadBytes('\n')
+		if resp, ok := r.subs.HandleMessage(r.ctx, stdioSessionID, line); ok {
+			if err := writeMessage(r.out, resp); err != nil {
+				return 0, err
+			}
+			line = nil
+		}
+		r.pending = line
+		if err != nil && len(r.pending) == 0 {
+			return 0, err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// writeMessage writes msg as one line of JSON, as the stdio transport does.
+func writeMessage(w io.Writer, msg any) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// newHTTPServer builds the HTTP server for the http or sse transport. The
+// streamable HTTP endpoint is /mcp; SSE uses /sse and /message. Both serve
+// /healthz for liveness probes.
+func newHTTPServer(s *server.MCPServer, subs *tools.Subscriptions, transport, addr string) (*http.Server, httpTransport) {
+	mux := http.NewServeMux()
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	var t httpTransport
+	if transport == transportSSE {
+		sse := server.NewSSEServer(s, server.WithHTTPServer(srv), server.WithSSEContextFunc(callerAPIKey))
+		mux.Handle(sse.CompleteSsePath(), sse)
+		mux.Handle(sse.CompleteMessagePath(), sse)
+		t = sse
+	} else {
+		t = server.NewStreamableHTTPServer(s, server.WithStreamableHTTPServer(srv), server.WithHTTPContextFunc(callerAPIKey))
+		mux.Handle("/mcp", subscriptionHandler(subs, t))
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok\n"))
+	})
+	return srv, t
+}
+
+// subscriptionHandler answers subscription requests posted to the streamable
+// HTTP endpoint and passes every other request to next. Notifications reach
+// the subscriber over the GET stream of its Mcp-Session-Id.
+func subscriptionHandler(subs *tools.Subscriptions, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		resp, ok := subs.HandleMessage(callerAPIKey(r.Context(), r), r.Header.Get(server.HeaderKeySessionID), body)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// callerAPIKey attaches the caller's Clockify API key, taken from the
+// X-Api-Key header or an Authorization bearer token, to the request context.
+// Keys are never logged.
+func callerAPIKey(ctx context.Context, r *http.Request) context.Context {
+	key := strings.TrimSpace(r.Header.Get("X-Api-Key"))
+	if key == "" {
+		if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			key = strings.TrimSpace(token)
+		}
+	}
+	if key == "" {
+		return ctx
+	}
+	return tools.WithAPIKey(ctx, key)
+}
+
+// serveHTTP listens until ctx is cancelled, then shuts the transport down,
+// letting in-flight requests finish within shutdownTimeout. Open GET streams
+// (streamable HTTP notifications and SSE) never finish on their own, so they
+// are closed first.
+func serveHTTP(ctx context.Context, srv *http.Server, t httpTransport) error {
+	streams, closeStreams := context.WithCancel(context.Background())
+	defer closeStreams()
+	srv.Handler = closeOnShutdown(streams, srv.Handler)
+
+	errc := make(chan error, 1)
+	go func() {
+		log.Printf("Listening on %s", srv.Addr)
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down")
+	closeStreams()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.Shutdown(shutdownCtx); errors.Is(err, context.DeadlineExceeded) {
+		log.Printf("Requests still open after %s, closing them", shutdownTimeout)
+		srv.Close()
+	} else if err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// closeOnShutdown cancels the context of GET requests to next once
+// shutdown is cancelled.
+func closeOnShutdown(shutdown context.Context, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			defer context.AfterFunc(shutdown, cancel)()
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
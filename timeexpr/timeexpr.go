@@ -0,0 +1,419 @@
+// Package timeexpr parses the loose time expressions people use for time
+// tracking ("yesterday 2-4pm", "last week", "1h30m starting at 9") and
+// normalizes them to the UTC timestamps the Clockify API expects.
+package timeexpr
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the UTC timestamp format sent to Clockify.
+const Layout = "2006-01-02T15:04:05Z"
+
+// maxOvernight is the longest span an interval whose end is a bare clock
+// time may get by ending the next day. Longer ones, such as "4pm to 2pm",
+// are more likely mistakes.
+const maxOvernight = 12 * time.Hour
+
+// Format renders t in Layout.
+func Format(t time.Time) string {
+	return t.UTC().Format(Layout)
+}
+
+// Parser interprets expressions relative to a clock and a time zone. The
+// zero value uses time.Now and UTC.
+type Parser struct {
+	// Now returns the current time.
+	Now func() time.Time
+	// Location is the zone for wall-clock times, date-only inputs and
+	// calendar periods.
+	Location *time.Location
+}
+
+func (p Parser) loc() *time.Location {
+	if p.Location != nil {
+		return p.Location
+	}
+	return time.UTC
+}
+
+func (p Parser) now() time.Time {
+	if p.Now != nil {
+		return p.Now().In(p.loc())
+	}
+	return time.Now().In(p.loc())
+}
+
+func (p Parser) today() time.Time {
+	n := p.now()
+	return time.Date(n.Year(), n.Month(), n.Day(), 0, 0, 0, 0, p.loc())
+}
+
+// Time parses a single instant: "now", an ISO 8601 timestamp, a date
+// ("2024-03-01", "yesterday", "last monday", "3 days ago"), a wall-clock time
+// ("9", "14:30", "2pm", "at noon"), or a date followed by a time
+// ("yesterday 2pm"). Dates without a time mean midnight.
+func (p Parser) Time(s string) (time.Time, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("empty time expression")
+	}
+	// Upper-case so timestamps survive the lower-casing done by Interval.
+	iso := strings.ToUpper(raw)
+	if t, err := time.Parse(time.RFC3339, iso); err == nil {
+		return t.In(p.loc()), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, iso, p.loc()); err == nil {
+			return t, nil
+		}
+	}
+
+	expr := normalize(raw)
+	if expr == "now" {
+		return p.now(), nil
+	}
+	if d, ok := p.parseDate(expr); ok {
+		return d, nil
+	}
+	if t, ok := p.parseDateClock(expr); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot understand time %q", s)
+}
+
+// Interval parses an explicit span: two instants ("9 to 17:30",
+// "yesterday 2-4pm", "2024-03-01T09:00:00Z to 2024-03-01T10:00:00Z"), or a
+// duration anchored at an instant ("1h30m starting at 9", "9am for 2h").
+// The end time may omit the date, in which case the start's date is used,
+// or the next day's when that ends the span at most maxOvernight after its
+// start ("22:00-01:00").
+func (p Parser) Interval(s string) (start, end time.Time, err error) {
+	expr := strings.TrimPrefix(normalize(s), "from ")
+
+	if m := durationFirstRe.FindStringSubmatch(expr); m != nil {
+		if d, derr := ParseDuration(m[1]); derr == nil {
+			start, err = p.Time(m[2])
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			return start, start.Add(d), nil
+		}
+	}
+	if m := durationLastRe.FindStringSubmatch(expr); m != nil {
+		if d, derr := ParseDuration(m[2]); derr == nil {
+			start, err = p.Time(m[1])
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			return start, start.Add(d), nil
+		}
+	}
+
+	from, to, ok := splitSpan(expr)
+	if !ok {
+		return time.Time{}, time.Time{}, fmt.Errorf("cannot understand interval %q (use e.g. \"9 to 17\" or \"1h30m starting at 9\")", s)
+	}
+
+	// "2-4pm": the start borrows the end's meridiem when it has none.
+	if toClock, ok := parseClock(to); ok && toClock.meridiem != "" {
+		datePart, fromClockStr := splitTrailingClock(from)
+		if fromClock, ok := parseClock(fromClockStr); ok && fromClock.meridiem == "" && fromClock.hour < 12 {
+			withMeridiem := fromClockStr + toClock.meridiem
+			if c, ok := parseClock(withMeridiem); ok && c.minutes() <= toClock.minutes() {
+				from = strings.TrimSpace(datePart + " " + withMeridiem)
+			}
+		}
+	}
+
+	start, err = p.Time(from)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if c, ok := parseClock(to); ok {
+		// Build each candidate from the date and clock rather than adding a
+		// day to the other, which would carry over a DST shift.
+		y, m, d := start.Date()
+		end = time.Date(y, m, d, c.hour, c.minute, 0, 0, start.Location())
+		if !end.After(start) {
+			// Measured on the wall clock, so a DST change does not tip
+			// "20:00 to 8:00" over maxOvernight.
+			startClock := clock{hour: start.Hour(), minute: start.Minute()}
+			if span := time.Duration(24*60-startClock.minutes()+c.minutes()) * time.Minute; span <= maxOvernight {
+				end = time.Date(y, m, d+1, c.hour, c.minute, 0, 0, start.Location())
+			}
+		}
+	} else if end, err = p.Time(to); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("interval %q ends before it starts", s)
+	}
+	return start, end, nil
+}
+
+// Range parses a calendar period or an explicit interval and returns the
+// half-open span [start, end). Periods are whole days, weeks (starting on
+// Monday), months or years: "today", "yesterday", "last friday",
+// "2024-03-01", "this week", "last month", "last 7 days".
+func (p Parser) Range(s string) (start, end time.Time, err error) {
+	expr := normalize(s)
+	today := p.today()
+
+	if d, ok := p.parseDate(expr); ok {
+		return d, d.AddDate(0, 0, 1), nil
+	}
+
+	switch expr {
+	case "this week", "last week", "next week":
+		monday := today.AddDate(0, 0, -daysSinceMonday(today))
+		switch expr {
+		case "last week":
+			monday = monday.AddDate(0, 0, -7)
+		case "next week":
+			monday = monday.AddDate(0, 0, 7)
+		}
+		return monday, monday.AddDate(0, 0, 7), nil
+	case "this month", "last month", "next month":
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, p.loc())
+		switch expr {
+		case "last month":
+			first = first.AddDate(0, -1, 0)
+		case "next month":
+			first = first.AddDate(0, 1, 0)
+		}
+		return first, first.AddDate(0, 1, 0), nil
+	case "this year", "last year":
+		first := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, p.loc())
+		if expr == "last year" {
+			first = first.AddDate(-1, 0, 0)
+		}
+		return first, first.AddDate(1, 0, 0), nil
+	}
+
+	if m := lastNRe.FindStringSubmatch(expr); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		days := n
+		if strings.HasPrefix(m[2], "week") {
+			days = n * 7
+		}
+		tomorrow := today.AddDate(0, 0, 1)
+		return tomorrow.AddDate(0, 0, -days), tomorrow, nil
+	}
+
+	return p.Interval(s)
+}
+
+// ParseDuration parses "1h30m", "90m", "1.5h", "1:30", "2 hours 15 minutes"
+// and similar. The duration must be positive.
+func ParseDuration(s string) (time.Duration, error) {
+	expr := normalize(s)
+	if m := clockDurationRe.FindStringSubmatch(expr); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		if min < 60 && (h > 0 || min > 0) {
+			return time.Duration(h)*time.Hour + time.Duration(min)*time.Minute, nil
+		}
+	}
+
+	compact := durationUnitRe.ReplaceAllStringFunc(expr, func(unit string) string {
+		switch {
+		case strings.HasPrefix(unit, "h"):
+			return "h"
+		case strings.HasPrefix(unit, "m"):
+			return "m"
+		default:
+			return "s"
+		}
+	})
+	compact = strings.NewReplacer(" ", "", "and", "", ",", "").Replace(compact)
+	d, err := time.ParseDuration(compact)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("cannot understand duration %q (use e.g. 1h30m or 90m)", s)
+	}
+	return d, nil
+}
+
+var (
+	spaceRe         = regexp.MustCompile(`\s+`)
+	meridiemRe      = regexp.MustCompile(`(\d)\s+(am|pm)\b`)
+	clockRe         = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	clockDurationRe = regexp.MustCompile(`^(\d+):(\d{2})$`)
+	durationUnitRe  = regexp.MustCompile(`hours?|hrs?|minutes?|mins?|seconds?|secs?`)
+	durationFirstRe = regexp.MustCompile(`^(.+?)\s+(?:starting at|starting|from|at)\s+(.+)$`)
+	durationLastRe  = regexp.MustCompile(`^(.+?)\s+for\s+(.+)$`)
+	daysAgoRe       = regexp.MustCompile(`^(\d+)\s+(days?|weeks?)\s+ago$`)
+	lastNRe         = regexp.MustCompile(`^(?:last|past)\s+(\d+)\s+(days?|weeks?)$`)
+	trailingClockRe = regexp.MustCompile(`^(.*?)\s*(\d{1,2}(?::\d{2})?(?:am|pm)?)$`)
+	clockSpanRe     = regexp.MustCompile(`^(.*?\d{1,2}(?::\d{2})?(?:am|pm)?)\s*-\s*(\d{1,2}(?::\d{2})?(?:am|pm)?)$`)
+)
+
+// normalize lowercases, collapses whitespace, joins "2 pm" into "2pm" and
+// turns en and em dashes into hyphens.
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("–", "-", "—", "-").Replace(s)
+	s = spaceRe.ReplaceAllString(s, " ")
+	return meridiemRe.ReplaceAllString(s, "$1$2")
+}
+
+// splitSpan splits "A to B", "A until B", "A - B" or "... 2-4pm".
+func splitSpan(expr string) (from, to string, ok bool) {
+	for _, sep := range []string{" to ", " until ", " - "} {
+		if a, b, found := strings.Cut(expr, sep); found {
+			return strings.TrimSpace(a), strings.TrimSpace(b), true
+		}
+	}
+	if m := clockSpanRe.FindStringSubmatch(expr); m != nil {
+		return strings.TrimSpace(m[1]), m[2], true
+	}
+	return "", "", false
+}
+
+// splitTrailingClock splits "yesterday 2" into "yesterday" and "2".
+func splitTrailingClock(expr string) (datePart, clock string) {
+	if m := trailingClockRe.FindStringSubmatch(expr); m != nil {
+		return m[1], m[2]
+	}
+	return expr, ""
+}
+
+type clock struct {
+	hour, minute int
+	meridiem     string
+}
+
+func (c clock) minutes() int { return c.hour*60 + c.minute }
+
+// parseClock parses "9", "09:30", "2pm", "2:30pm", "noon" and "midnight",
+// optionally prefixed with "at".
+func parseClock(s string) (clock, bool) {
+	s = strings.TrimPrefix(s, "at ")
+	switch s {
+	case "noon":
+		return clock{hour: 12}, true
+	case "midnight":
+		return clock{}, true
+	}
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return clock{}, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	min := 0
+	if m[2] != "" {
+		min, _ = strconv.Atoi(m[2])
+	}
+	if min > 59 {
+		return clock{}, false
+	}
+	c := clock{hour: h, minute: min, meridiem: m[3]}
+	if c.meridiem == "" {
+		return c, h <= 23
+	}
+	if h < 1 || h > 12 {
+		return clock{}, false
+	}
+	if c.meridiem == "am" && h == 12 {
+		c.hour = 0
+	} else if c.meridiem == "pm" && h != 12 {
+		c.hour += 12
+	}
+	return c, true
+}
+
+// parseDateClock parses an optional date followed by a wall-clock time. A
+// bare clock time means today.
+func (p Parser) parseDateClock(expr string) (time.Time, bool) {
+	expr = strings.TrimPrefix(expr, "at ")
+	if c, ok := parseClock(expr); ok {
+		d := p.today()
+		return time.Date(d.Year(), d.Month(), d.Day(), c.hour, c.minute, 0, 0, p.loc()), true
+	}
+	words := strings.Fields(expr)
+	for i := len(words) - 1; i > 0; i-- {
+		d, ok := p.parseDate(strings.Join(words[:i], " "))
+		if !ok {
+			continue
+		}
+		c, ok := parseClock(strings.Join(words[i:], " "))
+		if !ok {
+			return time.Time{}, false
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), c.hour, c.minute, 0, 0, p.loc()), true
+	}
+	return time.Time{}, false
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDate parses a date expression to local midnight.
+func (p Parser) parseDate(expr string) (time.Time, bool) {
+	today := p.today()
+	switch expr {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", expr, p.loc()); err == nil {
+		return t, true
+	}
+
+	if m := daysAgoRe.FindStringSubmatch(expr); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if strings.HasPrefix(m[2], "week") {
+			n *= 7
+		}
+		return today.AddDate(0, 0, -n), true
+	}
+
+	qualifier, name, found := strings.Cut(expr, " ")
+	if !found {
+		qualifier, name = "", expr
+	}
+	wd, ok := weekdays[name]
+	if !ok {
+		return time.Time{}, false
+	}
+	back := (int(today.Weekday()) - int(wd) + 7) % 7
+	switch qualifier {
+	case "":
+		// A bare weekday is the most recent one, today included.
+	case "last":
+		if back == 0 {
+			back = 7
+		}
+	case "this":
+		back = daysSinceMonday(today) - (int(wd)+6)%7
+	case "next":
+		forward := (int(wd) - int(today.Weekday()) + 7) % 7
+		if forward == 0 {
+			forward = 7
+		}
+		back = -forward
+	default:
+		return time.Time{}, false
+	}
+	return today.AddDate(0, 0, -back), true
+}
+
+// daysSinceMonday returns 0 for Monday through 6 for Sunday.
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
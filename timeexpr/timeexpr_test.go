@@ -0,0 +1,223 @@
+package timeexpr
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// cet is a fixed +01:00 zone so the tests do not depend on tzdata.
+var cet = time.FixedZone("CET", 3600)
+
+// fakeNow is Wednesday 2024-03-13 15:04:05 CET.
+var fakeNow = time.Date(2024, 3, 13, 15, 4, 5, 0, cet)
+
+func testParser() Parser {
+	return Parser{Now: func() time.Time { return fakeNow }, Location: cet}
+}
+
+func at(day, hour, min int) time.Time {
+	return time.Date(2024, 3, day, hour, min, 0, 0, cet)
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", fakeNow},
+		{"2024-03-01T09:00:00Z", time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{"2024-03-01T09:00:00+02:00", time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)},
+		{"2024-03-01 09:30", at(1, 9, 30)},
+		{"2024-03-01", at(1, 0, 0)},
+		{"today", at(13, 0, 0)},
+		{"Yesterday", at(12, 0, 0)},
+		{"tomorrow 8am", at(14, 8, 0)},
+		{"9", at(13, 9, 0)},
+		{"at 14:30", at(13, 14, 30)},
+		{"2pm", at(13, 14, 0)},
+		{"2 PM", at(13, 14, 0)},
+		{"12am", at(13, 0, 0)},
+		{"noon", at(13, 12, 0)},
+		{"yesterday at 2:15pm", at(12, 14, 15)},
+		{"monday", at(11, 0, 0)},
+		{"wednesday", at(13, 0, 0)},
+		{"last wednesday", at(6, 0, 0)},
+		{"last mon 9:00", at(11, 9, 0)},
+		{"this friday", at(15, 0, 0)},
+		{"next monday", at(18, 0, 0)},
+		{"3 days ago", at(10, 0, 0)},
+		{"2 weeks ago", time.Date(2024, 2, 28, 0, 0, 0, 0, cet)},
+	}
+
+	p := testParser()
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Time(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTime_Invalid(t *testing.T) {
+	p := testParser()
+	for _, in := range []string{"", "soonish", "25:00", "13pm", "last blursday", "yesterday 9:75"} {
+		if got, err := p.Time(in); err == nil {
+			t.Errorf("Time(%q) = %v, expected error", in, got)
+		}
+	}
+}
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end time.Time
+	}{
+		{"yesterday 2-4pm", at(12, 14, 0), at(12, 16, 0)},
+		{"yesterday 2–4pm", at(12, 14, 0), at(12, 16, 0)},
+		{"11-1pm", at(13, 11, 0), at(13, 13, 0)},
+		{"9 to 17:30", at(13, 9, 0), at(13, 17, 30)},
+		{"from 9am until noon", at(13, 9, 0), at(13, 12, 0)},
+		{"monday 9:00 - 10:15", at(11, 9, 0), at(11, 10, 15)},
+		{"1h30m starting at 9", at(13, 9, 0), at(13, 10, 30)},
+		{"90 minutes from yesterday 2pm", at(12, 14, 0), at(12, 15, 30)},
+		{"9am for 2 hours", at(13, 9, 0), at(13, 11, 0)},
+		{"2024-03-01T09:00:00Z to 2024-03-01T10:00:00Z",
+			time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)},
+		{"yesterday 22:00 to today 1:00", at(12, 22, 0), at(13, 1, 0)},
+		{"2024-03-12T23:30:00Z to 1:00", at(13, 0, 30), at(13, 1, 0)},
+		{"22:00-01:00", at(13, 22, 0), at(14, 1, 0)},
+		{"yesterday 11pm to 2am", at(12, 23, 0), at(13, 2, 0)},
+		{"20:00 to 8:00", at(13, 20, 0), at(14, 8, 0)},
+	}
+
+	p := testParser()
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			start, end, err := p.Interval(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Fatalf("got [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestInterval_OvernightAcrossDST(t *testing.T) {
+	prague, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2026, month, day, hour, min, 0, 0, prague)
+	}
+	tests := []struct {
+		in         string
+		start, end time.Time
+	}{
+		// Clocks go forward at 02:00 on 2026-03-29; 02:00 itself does not
+		// exist and normalizes to 03:00.
+		{"10pm to 2am", local(3, 28, 22, 0), local(3, 29, 3, 0)},
+		{"2026-03-29 22:00 to 2:30", local(3, 29, 22, 0), local(3, 30, 2, 30)},
+		// Clocks go back at 03:00 on 2026-10-25, making the night an hour
+		// longer.
+		{"2026-10-24 20:00 to 8:00", local(10, 24, 20, 0), local(10, 25, 8, 0)},
+		{"2026-10-25 22:00 to 2:30", local(10, 25, 22, 0), local(10, 26, 2, 30)},
+	}
+
+	p := Parser{Now: func() time.Time { return local(3, 28, 12, 0) }, Location: prague}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			start, end, err := p.Interval(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Fatalf("got [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestInterval_Invalid(t *testing.T) {
+	p := testParser()
+	for _, in := range []string{"yesterday", "last week", "4pm to 2pm", "soon to later"} {
+		if _, _, err := p.Interval(in); err == nil {
+			t.Errorf("Interval(%q) expected error", in)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end time.Time
+	}{
+		{"today", at(13, 0, 0), at(14, 0, 0)},
+		{"yesterday", at(12, 0, 0), at(13, 0, 0)},
+		{"last friday", at(8, 0, 0), at(9, 0, 0)},
+		{"2024-03-01", at(1, 0, 0), at(2, 0, 0)},
+		{"this week", at(11, 0, 0), at(18, 0, 0)},
+		{"last week", at(4, 0, 0), at(11, 0, 0)},
+		{"this month", at(1, 0, 0), time.Date(2024, 4, 1, 0, 0, 0, 0, cet)},
+		{"last month", time.Date(2024, 2, 1, 0, 0, 0, 0, cet), at(1, 0, 0)},
+		{"this year", time.Date(2024, 1, 1, 0, 0, 0, 0, cet), time.Date(2025, 1, 1, 0, 0, 0, 0, cet)},
+		{"last 7 days", at(7, 0, 0), at(14, 0, 0)},
+		{"past 2 weeks", time.Date(2024, 2, 29, 0, 0, 0, 0, cet), at(14, 0, 0)},
+		{"yesterday 2-4pm", at(12, 14, 0), at(12, 16, 0)},
+	}
+
+	p := testParser()
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			start, end, err := p.Range(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Fatalf("got [%v, %v), want [%v, %v)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{"90m", 90 * time.Minute},
+		{"1.5h", 90 * time.Minute},
+		{"1:30", 90 * time.Minute},
+		{"0:45", 45 * time.Minute},
+		{"2 hours 15 minutes", 135 * time.Minute},
+		{"1 hour and 30 mins", 90 * time.Minute},
+		{"45 min", 45 * time.Minute},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "90", "0m", "-1h", "1:75", "a while"} {
+		if got, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) = %v, expected error", in, got)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format(at(13, 9, 30)); got != "2024-03-13T08:30:00Z" {
+		t.Fatalf("got %s", got)
+	}
+}
@@ -0,0 +1,298 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// auditTarget names the entity a tool changes and the argument holding its
+// ID. Creates have no ID argument; the ID is taken from Clockify's response.
+type auditTarget struct {
+	entity string
+	idArg  string
+	// running marks tools that act on the running timer.
+	running bool
+}
+
+var auditTargets = map[string]auditTarget{
+	"clockify_timer_start":       {entity: "time_entry"},
+	"clockify_timer_stop":        {entity: "time_entry", running: true},
+	"clockify_timer_switch":      {entity: "time_entry"},
+	"clockify_timer_resume":      {entity: "time_entry"},
+	"clockify_time_entry_create": {entity: "time_entry"},
+	"clockify_time_entry_update": {entity: "time_entry", idArg: "entry_id"},
+	"clockify_time_entry_delete": {entity: "time_entry", idArg: "entry_id"},
+	"clockify_project_create":    {entity: "project"},
+	"clockify_project_update":    {entity: "project", idArg: "project_id"},
+	"clockify_project_delete":    {entity: "project", idArg: "project_id"},
+	"clockify_task_create":       {entity: "task"},
+	"clockify_task_update":       {entity: "task", idArg: "task_id"},
+	"clockify_task_delete":       {entity: "task", idArg: "task_id"},
+	"clockify_tag_create":        {entity: "tag"},
+	"clockify_tag_update":        {entity: "tag", idArg: "tag_id"},
+	"clockify_tag_delete":        {entity: "tag", idArg: "tag_id"},
+	"clockify_client_create":     {entity: "client"},
+	"clockify_client_update":     {entity: "client", idArg: "client_id"},
+	"clockify_client_delete":     {entity: "client", idArg: "client_id"},
+}
+
+// withAudit records each call of the named tool that sends a write to
+// Clockify, with the entity's state before and after it. Dry runs and calls
+// that fail before reaching Clockify are not recorded.
+func (r *registry) withAudit(name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	target := auditTargets[name]
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if r.isDryRun(req) {
+			return next(ctx, req)
+		}
+
+		rec := audit.Record{
+			Tool:        name,
+			Args:        req.GetArguments(),
+			WorkspaceID: r.workspaceID(ctx, req.GetString("workspace_id", "")),
+			Entity:      target.entity,
+		}
+		r.auditBefore(ctx, req, target, &rec)
+
+		var writes writeLog
+		ctx = context.WithValue(clockify.WithWriteObserver(ctx, writes.observe), writeLogKey{}, &writes)
+		result, err := next(ctx, req)
+
+		rec.Responses, rec.After = writes.responses, writes.after
+		if writes.before != nil {
+			rec.EntityID, rec.Before = writes.beforeID, writes.before
+		}
+		if len(rec.Responses) == 0 {
+			return result, err
+		}
+		if len(rec.After) > 0 {
+			rec.After = entityJSON(target.entity, rec.After)
+			if rec.EntityID == "" {
+				rec.EntityID = entityID(rec.After)
+			}
+		}
+		if err != nil {
+			rec.Error = err.Error()
+		} else if result != nil && result.IsError && len(result.Content) > 0 {
+			if tc, ok := result.Content[0].(mcp.TextContent); ok {
+				rec.Error = tc.Text
+			}
+		}
+		if jerr := r.appendRecord(ctx, &rec); jerr != nil && result != nil {
+			result.Content = append(result.Content, mcp.NewTextContent("Warning: the change was made but not recorded in the audit log: "+jerr.Error()))
+		}
+		return result, err
+	}
+}
+
+// writeLog collects Clockify's responses to the writes of one call. after
+// is the body of the last successful write, or nil if that was a delete.
+// before is set by handlers through recordBefore.
+type writeLog struct {
+	mu        sync.Mutex
+	responses []audit.Response
+	after     json.RawMessage
+	beforeID  string
+	before    json.RawMessage
+}
+
+type writeLogKey struct{}
+
+// recordBefore gives the audit record of the call behind ctx the state of
+// the entity it is about to update, for tools that only decide while
+// running whether they update an entity or create one. It does nothing
+// when the call is not audited.
+func recordBefore(ctx context.Context, id string, state any) {
+	w, _ := ctx.Value(writeLogKey{}).(*writeLog)
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.beforeID = id
+	w.before, _ = json.Marshal(state)
+}
+
+func (w *writeLog) observe(method, path string, status int, body []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.responses = append(w.responses, audit.Response{Method: method, Path: path, Status: status})
+	if status >= 200 && status < 300 {
+		w.after = nil
+		if method != http.MethodDelete {
+			w.after = body
+		}
+	}
+}
+
+// appendRecord stamps rec with the caller's identity and writes it to the
+// journal.
+func (r *registry) appendRecord(ctx context.Context, rec *audit.Record) error {
+	if user, err := r.currentUser(ctx); err == nil {
+		rec.UserID, rec.UserName = user.ID, user.Name
+	}
+	if session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo); ok {
+		rec.Client = session.GetClientInfo().Name
+	}
+	return r.journal.Append(rec)
+}
+
+// entityID returns the id field of an entity's JSON.
+func entityID(state json.RawMessage) string {
+	var v struct {
+		ID string `json:"id"`
+	}
+	json.Unmarshal(state, &v)
+	return v.ID
+}
+
+// auditBefore fills in the ID and current state of the entity a call is
+// about to change. Lookups that fail leave them empty; the call itself will
+// report the problem.
+func (r *registry) auditBefore(ctx context.Context, req mcp.CallToolRequest, target auditTarget, rec *audit.Record) {
+	if target.running {
+		user, err := r.currentUser(ctx)
+		if err != nil {
+			return
+		}
+		entry, err := r.client(ctx).GetRunningTimer(ctx, rec.WorkspaceID, user.ID)
+		if err != nil || entry == nil {
+			return
+		}
+		rec.EntityID = entry.ID
+		rec.Before, _ = json.Marshal(entry)
+		return
+	}
+	if target.idArg == "" {
+		return
+	}
+	rec.EntityID = req.GetString(target.idArg, "")
+	if rec.EntityID == "" {
+		return
+	}
+	var projectID string
+	if target.entity == "task" {
+		projectID, _ = r.projectArg(ctx, req, rec.WorkspaceID)
+	}
+	state, err := r.entityState(ctx, target.entity, rec.WorkspaceID, projectID, rec.EntityID)
+	if err == nil {
+		rec.Before, _ = json.Marshal(state)
+	}
+}
+
+// entityState fetches one entity of the given kind. projectID is only used
+// for tasks.
+func (r *registry) entityState(ctx context.Context, entity, wsID, projectID, id string) (any, error) {
+	c := r.client(ctx)
+	switch entity {
+	case "time_entry":
+		return c.GetTimeEntry(ctx, wsID, id)
+	case "project":
+		return c.GetProject(ctx, wsID, id)
+	case "task":
+		return c.GetTask(ctx, wsID, projectID, id)
+	case "tag":
+		return c.GetTag(ctx, wsID, id)
+	case "client":
+		return c.GetClient(ctx, wsID, id)
+	}
+	return nil, nil
+}
+
+// entityJSON trims a Clockify response down to the fields of its entity
+// type, so records hold the same shape before and after. Responses of
+// unknown kinds are kept as they are.
+func entityJSON(entity string, body []byte) json.RawMessage {
+	var v any
+	switch entity {
+	case "time_entry":
+		v = &clockify.TimeEntry{}
+	case "project":
+		v = &clockify.Project{}
+	case "task":
+		v = &clockify.Task{}
+	case "tag":
+		v = &clockify.Tag{}
+	case "client":
+		v = &clockify.ClockifyClient{}
+	default:
+		return body
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return body
+	}
+	b, _ := json.Marshal(v)
+	return b
+}
+
+// defaultAuditLimit is how many records clockify_audit_query returns when
+// no limit is given.
+const defaultAuditLimit = 20
+
+func registerAuditTools(s *server.MCPServer, r *registry) {
+	if r.journal == nil {
+		return
+	}
+	r.addTool(s,
+		mcp.NewTool("clockify_audit_query",
+			mcp.WithDescription("Search the audit log of changes made to Clockify through this server, newest first. Each record holds the tool, its arguments, who made the call, the entity's state before and after, and Clockify's response status."),
+			localReadOnlyTool("Search audit log"),
+			mcp.WithOutputSchema[auditQueryOutput](),
+			mcp.WithString("tool", mcp.Description("Tool name or glob pattern, e.g. 'clockify_time_entry_*'")),
+			mcp.WithString("entity", mcp.Description("Kind of entity changed"), mcp.Enum("time_entry", "project", "task", "tag", "client")),
+			mcp.WithString("entity_id", mcp.Description("ID of the entity changed")),
+			mcp.WithString("range", mcp.Description("Period to search, e.g. 'today', 'last week'")),
+			mcp.WithString("start", mcp.Description("Earliest change time (ISO 8601 or natural); overrides the start of range")),
+			mcp.WithString("end", mcp.Description("Latest change time (ISO 8601 or natural); overrides the end of range")),
+			mcp.WithString("text", mcp.Description("Only records containing this text anywhere, ignoring case")),
+			mcp.WithNumber("limit", mcp.Description("Maximum records to return (default 20)")),
+		),
+		auditQueryHandler(r),
+	)
+}
+
+func auditQueryHandler(r *registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		p, err := r.parser(ctx)
+		if err != nil {
+			return apiErrorResult("get current user", err, "", ""), nil
+		}
+		start, end, err := filterRange(p, req, false)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		q := audit.Query{
+			Tool:     req.GetString("tool", ""),
+			Entity:   req.GetString("entity", ""),
+			EntityID: req.GetString("entity_id", ""),
+			Text:     req.GetString("text", ""),
+			Limit:    req.GetInt("limit", defaultAuditLimit),
+		}
+		q.Since, _ = time.Parse(time.RFC3339, start)
+		q.Until, _ = time.Parse(time.RFC3339, end)
+
+		// Callers with their own API key only see their own changes.
+		if r.session(ctx) != r.base {
+			user, err := r.currentUser(ctx)
+			if err != nil {
+				return apiErrorResult("get current user", err, "", ""), nil
+			}
+			q.UserID = user.ID
+		}
+
+		records, err := r.journal.Query(q)
+		if err != nil {
+			return mcp.NewToolResultError("Failed to read audit log: " + err.Error()), nil
+		}
+		return resultJSON(auditQueryOutput{Records: records})
+	}
+}
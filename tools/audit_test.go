@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+func newTestJournal(t *testing.T) *audit.Journal {
+	t.Helper()
+	j, err := audit.Open(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { j.Close() })
+	return j
+}
+
+func TestAudit_RecordsUpdateWithBeforeAndAfter(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+	r.journal = newTestJournal(t)
+	handler := r.withAudit("clockify_time_entry_update", timeEntryUpdateHandler(r))
+
+	callTool(t, handler, map[string]any{"entry_id": "e1", "description": "Daily standup"})
+	// Neither a dry run nor a call rejected before reaching Clockify is recorded.
+	callTool(t, handler, map[string]any{"entry_id": "e1", "description": "Retro", "dry_run": true})
+	callTool(t, handler, map[string]any{"entry_id": "e1"})
+
+	records, err := r.journal.Query(audit.Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.Tool != "clockify_time_entry_update" || rec.Entity != "time_entry" || rec.EntityID != "e1" ||
+		rec.WorkspaceID != "ws1" || rec.UserID != "u1" || rec.Args["description"] != "Daily standup" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	if len(rec.Responses) != 1 || rec.Responses[0] != (audit.Response{Method: "PUT", Path: "/workspaces/ws1/time-entries/e1", Status: 200}) {
+		t.Fatalf("unexpected responses: %+v", rec.Responses)
+	}
+	var before, after clockify.TimeEntry
+	json.Unmarshal(rec.Before, &before)
+	json.Unmarshal(rec.After, &after)
+	if before.Description != "Standup" || after.Description != "Daily standup" {
+		t.Fatalf("unexpected states: before %s, after %s", rec.Before, rec.After)
+	}
+}
+
+func TestAudit_RecordsCreateWithEveryWrite(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+	r.journal = newTestJournal(t)
+	handler := r.withAudit("clockify_timer_start", timerStartHandler(r))
+
+	callTool(t, handler, map[string]any{"tags": []any{"Urgent"}, "create_missing": true})
+
+	records, _ := r.journal.Query(audit.Query{})
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.EntityID != "e1" || rec.Before != nil || len(rec.Responses) != 2 ||
+		rec.Responses[0].Path != "/workspaces/ws1/tags" || rec.Responses[1].Path != "/workspaces/ws1/time-entries" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+}
+
+func TestAuditQuery_FiltersRecords(t *testing.T) {
+	r := newTestRegistry(t, &entryServer{entry: storedEntry()})
+	r.journal = newTestJournal(t)
+	for _, rec := range []audit.Record{
+		{Tool: "clockify_time_entry_update", Entity: "time_entry", EntityID: "e1"},
+		{Tool: "clockify_project_delete", Entity: "project", EntityID: "p1"},
+	} {
+		r.journal.Append(&rec)
+	}
+
+	result := callTool(t, auditQueryHandler(r), map[string]any{"entity": "project"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var out auditQueryOutput
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if len(out.Records) != 1 || out.Records[0].EntityID != "p1" {
+		t.Fatalf("unexpected records: %s", resultText(t, result))
+	}
+
+	result = callTool(t, auditQueryHandler(r), map[string]any{"range": "someday"})
+	if !result.IsError || !strings.Contains(resultText(t, result), "invalid range") {
+		t.Fatalf("expected an invalid range error, got %s", resultText(t, result))
+	}
+}
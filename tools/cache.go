@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// defaultCacheTTL is how long cached metadata is reused when Options.CacheTTL
+// is zero.
+const defaultCacheTTL = 5 * time.Minute
+
+// ttlCache memoizes slow-changing Clockify metadata (current user, workspaces,
+// full project/task/tag/client listings) for a fixed duration. Keys are
+// scoped by workspace as "<kind>:<workspaceID>" so writes can invalidate one
+// workspace.
+type ttlCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// newTTLCache returns a cache with the given TTL. A negative TTL disables
+// caching; zero selects defaultCacheTTL.
+func newTTLCache(ttl time.Duration) *ttlCache {
+	if ttl == 0 {
+		ttl = defaultCacheTTL
+	}
+	return &ttlCache{ttl: ttl, now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+func (c *ttlCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *ttlCache) set(key string, value any) {
+	if c.ttl < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: value, expires: c.now().Add(c.ttl)}
+}
+
+// invalidate drops the given keys and every key scoped under them.
+func (c *ttlCache) invalidate(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.entries {
+		for _, key := range keys {
+			if k == key || strings.HasPrefix(k, key+":") {
+				delete(c.entries, k)
+				break
+			}
+		}
+	}
+}
+
+// clear drops every entry and returns how many there were.
+func (c *ttlCache) clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.entries)
+	c.entries = map[string]cacheEntry{}
+	return n
+}
+
+// cached returns the value stored under key, loading and storing it on a miss.
+// Failed loads are not cached.
+func cached[T any](c *ttlCache, key string, load func() (T, error)) (T, error) {
+	if v, ok := c.get(key); ok {
+		return v.(T), nil
+	}
+	v, err := load()
+	if err != nil {
+		return v, err
+	}
+	c.set(key, v)
+	return v, nil
+}
+
+// listing is a cached full listing; truncated mirrors clockify.Collect.
+type listing[T any] struct {
+	items     []T
+	truncated bool
+}
+
+func cachedListing[T any](c *ttlCache, key string, load func() ([]T, bool, error)) (listing[T], error) {
+	return cached(c, key, func() (listing[T], error) {
+		items, truncated, err := load()
+		return listing[T]{items: items, truncated: truncated}, err
+	})
+}
+
+func projectsKey(wsID string) string { return "projects:" + wsID }
+func tagsKey(wsID string) string     { return "tags:" + wsID }
+func clientsKey(wsID string) string  { return "clients:" + wsID }
+
+func tasksKey(wsID, projectID string) string {
+	return "tasks:" + wsID + ":" + projectID
+}
+
+func (r *registry) currentUser(ctx context.Context) (*clockify.User, error) {
+	return cached(r.cache(ctx), "user", func() (*clockify.User, error) {
+		return r.client(ctx).GetCurrentUser(ctx)
+	})
+}
+
+func (r *registry) workspaces(ctx context.Context) ([]clockify.Workspace, error) {
+	return cached(r.cache(ctx), "workspaces", func() ([]clockify.Workspace, error) {
+		return r.client(ctx).GetWorkspaces(ctx)
+	})
+}
+
+func (r *registry) allProjects(ctx context.Context, wsID string, archived bool) (listing[clockify.Project], error) {
+	key := projectsKey(wsID) + ":active"
+	if archived {
+		key = projectsKey(wsID) + ":archived"
+	}
+	return cachedListing(r.cache(ctx), key, func() ([]clockify.Project, bool, error) {
+		return clockify.Collect(r.client(ctx).AllProjects(ctx, wsID, archived))
+	})
+}
+
+func (r *registry) allTasks(ctx context.Context, wsID, projectID string) (listing[clockify.Task], error) {
+	return cachedListing(r.cache(ctx), tasksKey(wsID, projectID), func() ([]clockify.Task, bool, error) {
+		return clockify.Collect(r.client(ctx).AllTasks(ctx, wsID, projectID))
+	})
+}
+
+func (r *registry) allTags(ctx context.Context, wsID string) (listing[clockify.Tag], error) {
+	return cachedListing(r.cache(ctx), tagsKey(wsID), func() ([]clockify.Tag, bool, error) {
+		return clockify.Collect(r.client(ctx).AllTags(ctx, wsID))
+	})
+}
+
+func (r *registry) allClients(ctx context.Context, wsID string) (listing[clockify.ClockifyClient], error) {
+	return cachedListing(r.cache(ctx), clientsKey(wsID), func() ([]clockify.ClockifyClient, bool, error) {
+		return clockify.Collect(r.client(ctx).AllClients(ctx, wsID))
+	})
+}
+
+func registerCacheTools(s *server.MCPServer, r *registry) {
+	r.addTool(s,
+		mcp.NewTool("clockify_cache_refresh",
+			mcp.WithDescription("Drop cached Clockify metadata (current user, workspaces, projects, tags, clients) so the next call fetches fresh data"),
+			localTool("Refresh cache"),
+			mcp.WithOutputSchema[cacheRefreshOutput](),
+		),
+		cacheRefreshHandler(r),
+	)
+}
+
+func cacheRefreshHandler(r *registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return resultJSON(cacheRefreshOutput{Cleared: r.cache(ctx).clear()})
+	}
+}
@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTTLCache_ExpiresAfterTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newTTLCache(time.Minute)
+	c.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() (string, error) {
+		loads++
+		return "value", nil
+	}
+
+	for range 3 {
+		if v, err := cached(c, "k", load); err != nil || v != "value" {
+			t.Fatalf("unexpected result: %q, %v", v, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected 1 load within TTL, got %d", loads)
+	}
+
+	now = now.Add(time.Minute)
+	cached(c, "k", load)
+	if loads != 2 {
+		t.Fatalf("expected reload after TTL, got %d loads", loads)
+	}
+}
+
+func TestTTLCache_DoesNotCacheErrors(t *testing.T) {
+	c := newTTLCache(time.Minute)
+	loads := 0
+	load := func() (int, error) {
+		loads++
+		return 0, errors.New("boom")
+	}
+	cached(c, "k", load)
+	cached(c, "k", load)
+	if loads != 2 {
+		t.Fatalf("expected failed loads to be retried, got %d loads", loads)
+	}
+}
+
+func TestTTLCache_InvalidateByScope(t *testing.T) {
+	c := newTTLCache(time.Minute)
+	c.set("projects:ws1:active", 1)
+	c.set("projects:ws1:archived", 2)
+	c.set("projects:ws2:active", 3)
+	c.set("tags:ws1", 4)
+
+	c.invalidate(projectsKey("ws1"))
+
+	for key, want := range map[string]bool{
+		"projects:ws1:active":   false,
+		"projects:ws1:archived": false,
+		"projects:ws2:active":   true,
+		"tags:ws1":              true,
+	} {
+		if _, ok := c.get(key); ok != want {
+			t.Errorf("%s cached=%v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestTTLCache_NegativeTTLDisables(t *testing.T) {
+	c := newTTLCache(-1)
+	c.set("k", 1)
+	if _, ok := c.get("k"); ok {
+		t.Fatal("expected disabled cache to miss")
+	}
+}
+
+func TestProjectList_AllIsCachedUntilWrite(t *testing.T) {
+	var listCalls atomic.Int32
+	r := newTestRegistry(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+			listCalls.Add(1)
+			w.Write([]byte(`[{"id":"p1","name":"Website"}]`))
+		case http.MethodPost:
+			w.Write([]byte(`{"id":"p2","name":"New"}`))
+		}
+	}))
+
+	list := projectListHandler(r)
+	for range 2 {
+		if text := resultText(t, callTool(t, list, map[string]any{"all": true})); !strings.Contains(text, "Website") {
+			t.Fatalf("unexpected result: %s", text)
+		}
+	}
+	if listCalls.Load() != 1 {
+		t.Fatalf("expected cached listing, got %d list calls", listCalls.Load())
+	}
+
+	callTool(t, projectCreateHandler(r), map[string]any{"name": "New"})
+	callTool(t, list, map[string]any{"all": true})
+	if listCalls.Load() != 2 {
+		t.Fatalf("expected create to invalidate the cache, got %d list calls", listCalls.Load())
+	}
+
+	callTool(t, cacheRefreshHandler(r), nil)
+	callTool(t, list, map[string]any{"all": true})
+	if listCalls.Load() != 3 {
+		t.Fatalf("expected refresh to clear the cache, got %d list calls", listCalls.Load())
+	}
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,9 +9,12 @@ import (
 )
 
 func registerClientTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_client_list",
-			mcp.WithDescription("List clients in a workspace (paginated, default page 1, page_size 50)"),
+			mcp.WithDescription("List clients in a workspace (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List clients"),
+			mcp.WithOutputSchema[clientListOutput](),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of clients per page (default 50)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -20,18 +22,22 @@ func registerClientTools(s *server.MCPServer, r *registry) {
 		clientListHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_client_create",
 			mcp.WithDescription("Create a new client"),
+			createTool("Create client"),
+			mcp.WithOutputSchema[clockify.ClockifyClient](),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Client name")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		clientCreateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_client_update",
 			mcp.WithDescription("Update a client"),
+			updateTool("Update client"),
+			mcp.WithOutputSchema[clockify.ClockifyClient](),
 			mcp.WithString("client_id", mcp.Required(), mcp.Description("Client ID to update")),
 			mcp.WithString("name", mcp.Description("New client name")),
 			mcp.WithBoolean("archived", mcp.Description("Whether the client is archived")),
@@ -40,9 +46,10 @@ func registerClientTools(s *server.MCPServer, r *registry) {
 		clientUpdateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_client_delete",
 			mcp.WithDescription("Delete a client"),
+			deleteTool("Delete client"),
 			mcp.WithString("client_id", mcp.Required(), mcp.Description("Client ID to delete")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
@@ -52,26 +59,34 @@ func registerClientTools(s *server.MCPServer, r *registry) {
 
 func clientListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
+		if req.GetBool("all", false) {
+			list, err := r.allClients(ctx, wsID)
+			if err != nil {
+				return apiErrorResult("list clients", err, "", wsID), nil
+			}
+			return resultJSON(clientListOutput{Clients: list.items, Truncated: list.truncated})
+		}
+
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		clients, err := r.client.GetClients(wsID, page, pageSize)
+		clients, err := r.client(ctx).GetClients(ctx, wsID, page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list clients: %v", err)), nil
+			return apiErrorResult("list clients", err, "", wsID), nil
 		}
 
-		return resultJSON(map[string]any{"clients": clients})
+		return resultJSON(clientListOutput{Clients: clients})
 	}
 }
 
 func clientCreateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -81,10 +96,20 @@ func clientCreateHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("name is required"), nil
 		}
 
-		client, err := r.client.CreateClient(wsID, clockify.CreateClientRequest{Name: name})
+		createReq := clockify.CreateClientRequest{Name: name}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "CreateClient",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: createReq,
+			})
+		}
+
+		client, err := r.client(ctx).CreateClient(ctx, wsID, createReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create client: %v", err)), nil
+			return apiErrorResult("create client", err, "", wsID), nil
 		}
+		r.cache(ctx).invalidate(clientsKey(wsID))
 
 		return resultJSON(client)
 	}
@@ -92,7 +117,7 @@ func clientCreateHandler(r *registry) server.ToolHandlerFunc {
 
 func clientUpdateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -112,10 +137,24 @@ func clientUpdateHandler(r *registry) server.ToolHandlerFunc {
 			updateReq.Archived = &a
 		}
 
-		client, err := r.client.UpdateClient(wsID, clientID, updateReq)
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetClient(ctx, wsID, clientID)
+			if err != nil {
+				return apiErrorResult("get client", err, "client "+clientID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "UpdateClient",
+				Args:    map[string]string{"workspace_id": wsID, "client_id": clientID},
+				Request: updateReq,
+				Current: current,
+			})
+		}
+
+		client, err := r.client(ctx).UpdateClient(ctx, wsID, clientID, updateReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to update client: %v", err)), nil
+			return apiErrorResult("update client", err, "client "+clientID, wsID), nil
 		}
+		r.cache(ctx).invalidate(clientsKey(wsID))
 
 		return resultJSON(client)
 	}
@@ -123,7 +162,7 @@ func clientUpdateHandler(r *registry) server.ToolHandlerFunc {
 
 func clientDeleteHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -133,9 +172,29 @@ func clientDeleteHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("client_id is required"), nil
 		}
 
-		if err := r.client.DeleteClient(wsID, clientID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete client: %v", err)), nil
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetClient(ctx, wsID, clientID)
+			if err != nil {
+				return apiErrorResult("get client", err, "client "+clientID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "DeleteClient",
+				Args:    map[string]string{"workspace_id": wsID, "client_id": clientID},
+				Current: current,
+			})
+		}
+
+		if result := r.confirmDelete(ctx, req, "clockify_client_delete", wsID, clientID, func() (any, *mcp.CallToolResult) {
+			return r.clientDeletePreview(ctx, wsID, clientID)
+		}); result != nil {
+			return result, nil
+		}
+
+		if err := r.client(ctx).DeleteClient(ctx, wsID, clientID); err != nil {
+			return apiErrorResult("delete client", err, "client "+clientID, wsID), nil
 		}
+		// Deleting a client detaches it from its projects.
+		r.cache(ctx).invalidate(clientsKey(wsID), projectsKey(wsID))
 
 		return mcp.NewToolResultText("Client deleted successfully."), nil
 	}
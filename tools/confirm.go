@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
+)
+
+// confirmTokenTTL is how long a delete confirmation token stays valid.
+const confirmTokenTTL = 5 * time.Minute
+
+// DefaultConfirmDeletes are the delete tools that need a confirmation token
+// when Options.ConfirmDeletes is nil.
+var DefaultConfirmDeletes = []string{"clockify_project_delete", "clockify_client_delete"}
+
+// confirmableDeletes are the delete tools that can ask for confirmation.
+var confirmableDeletes = []string{"clockify_project_delete", "clockify_client_delete", "clockify_time_entry_delete"}
+
+// CheckConfirmDeletes reports the first name in tools that cannot ask for
+// confirmation.
+func CheckConfirmDeletes(tools []string) error {
+	for _, name := range tools {
+		if !slices.Contains(confirmableDeletes, name) {
+			return fmt.Errorf("%s does not support delete confirmation (supported: %v)", name, confirmableDeletes)
+		}
+	}
+	return nil
+}
+
+// pendingDelete is a deletion a token was issued for. A token only confirms
+// the same tool, workspace and entity, in the same caller's session.
+type pendingDelete struct {
+	tool    string
+	wsID    string
+	id      string
+	session *session
+}
+
+// confirmations holds the outstanding confirmation tokens.
+type confirmations struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	pending map[string]confirmation
+}
+
+type confirmation struct {
+	pendingDelete
+	expires time.Time
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{now: time.Now, pending: map[string]confirmation{}}
+}
+
+// issue returns a new token for p and when it expires.
+func (c *confirmations) issue(p pendingDelete) (string, time.Time) {
+	b := make([]byte, 4)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for t, conf := range c.pending {
+		if !now.Before(conf.expires) {
+			delete(c.pending, t)
+		}
+	}
+	expires := now.Add(confirmTokenTTL)
+	c.pending[token] = confirmation{pendingDelete: p, expires: expires}
+	return token, expires
+}
+
+// redeem reports whether token confirms p, using the token up if so.
+func (c *confirmations) redeem(token string, p pendingDelete) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conf, ok := c.pending[token]
+	if !ok || conf.pendingDelete != p || !c.now().Before(conf.expires) {
+		return false
+	}
+	delete(c.pending, token)
+	return true
+}
+
+// confirmTokenArg adds the confirm_token argument to a delete tool that asks
+// for confirmation.
+func confirmTokenArg(tool *mcp.Tool) {
+	mcp.WithString("confirm_token",
+		mcp.Description("Token from a previous call's preview. Without it the tool only previews what would be deleted and returns a token valid for 5 minutes"),
+	)(tool)
+}
+
+// deleteConfirmation is returned instead of deleting when a token is needed.
+type deleteConfirmation struct {
+	ConfirmationRequired bool      `json:"confirmation_required"`
+	ConfirmToken         string    `json:"confirm_token"`
+	ExpiresAt            time.Time `json:"expires_at"`
+	Preview              any       `json:"preview"`
+}
+
+// confirmDelete gates a delete behind a confirmation token when tool asks
+// for one. It returns nil when the delete may go ahead; otherwise the
+// result to return: a preview with a new token, or an error for a bad token.
+// preview describes what would be deleted, or returns an error result.
+func (r *registry) confirmDelete(ctx context.Context, req mcp.CallToolRequest, tool, wsID, id string, preview func() (any, *mcp.CallToolResult)) *mcp.CallToolResult {
+	if !slices.Contains(r.confirmDeletes, tool) {
+		return nil
+	}
+	p := pendingDelete{tool: tool, wsID: wsID, id: id, session: r.session(ctx)}
+	if token := req.GetString("confirm_token", ""); token != "" {
+		if r.confirmations.redeem(token, p) {
+			return nil
+		}
+		return mcp.NewToolResultError("confirm_token is invalid, expired or was issued for another deletion; call again without it for a new preview and token")
+	}
+
+	details, errResult := preview()
+	if errResult != nil {
+		return errResult
+	}
+	token, expires := r.confirmations.issue(p)
+	result, err := resultJSON(deleteConfirmation{
+		ConfirmationRequired: true,
+		ConfirmToken:         token,
+		ExpiresAt:            expires.UTC(),
+		Preview:              details,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(err.Error())
+	}
+	return result
+}
+
+// Previews of what a delete removes. TimeEntries is nil, with the reason in
+// TimeEntriesError, when the reports API could not count them.
+
+type projectDeletePreview struct {
+	Project          clockify.Project `json:"project"`
+	Tasks            int              `json:"tasks"`
+	TimeEntries      *int             `json:"time_entries,omitempty"`
+	TimeEntriesError string           `json:"time_entries_error,omitempty"`
+}
+
+type clientDeletePreview struct {
+	Client           clockify.ClockifyClient `json:"client"`
+	Projects         []string                `json:"projects"`
+	TimeEntries      *int                    `json:"time_entries,omitempty"`
+	TimeEntriesError string                  `json:"time_entries_error,omitempty"`
+}
+
+type timeEntryDeletePreview struct {
+	Entry clockify.TimeEntry `json:"entry"`
+}
+
+// countEntries counts every time entry in the workspace the filter of req
+// matches, by asking the detailed report for a one-entry page.
+func (r *registry) countEntries(ctx context.Context, wsID string, req clockify.DetailedReportRequest) (*int, string) {
+	req.DateRangeStart = timeexpr.Format(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	req.DateRangeEnd = inclusiveReportEnd(time.Now().UTC().AddDate(0, 0, 1))
+	req.Page, req.PageSize = 1, 1
+	req.DetailedFilter = &clockify.DetailedFilter{Page: 1, PageSize: 1}
+	report, err := r.client(ctx).GetDetailedReport(ctx, wsID, req)
+	if err != nil {
+		return nil, describeAPIError(err, "", wsID)
+	}
+	return &report.TotalCount, ""
+}
+
+func (r *registry) projectDeletePreview(ctx context.Context, wsID, projectID string) (any, *mcp.CallToolResult) {
+	project, err := r.client(ctx).GetProject(ctx, wsID, projectID)
+	if err != nil {
+		return nil, apiErrorResult("get project", err, "project "+projectID, wsID)
+	}
+	tasks, err := r.allTasks(ctx, wsID, projectID)
+	if err != nil {
+		return nil, apiErrorResult("list tasks", err, "project "+projectID, wsID)
+	}
+	preview := projectDeletePreview{Project: *project, Tasks: len(tasks.items)}
+	preview.TimeEntries, preview.TimeEntriesError = r.countEntries(ctx, wsID, clockify.DetailedReportRequest{
+		Projects: &clockify.ReportProjectFilter{IDs: []string{projectID}},
+	})
+	return preview, nil
+}
+
+func (r *registry) clientDeletePreview(ctx context.Context, wsID, clientID string) (any, *mcp.CallToolResult) {
+	client, err := r.client(ctx).GetClient(ctx, wsID, clientID)
+	if err != nil {
+		return nil, apiErrorResult("get client", err, "client "+clientID, wsID)
+	}
+	preview := clientDeletePreview{Client: *client, Projects: []string{}}
+	for _, archived := range []bool{false, true} {
+		projects, err := r.allProjects(ctx, wsID, archived)
+		if err != nil {
+			return nil, apiErrorResult("list projects", err, "", wsID)
+		}
+		for _, p := range projects.items {
+			if p.ClientID == clientID {
+				preview.Projects = append(preview.Projects, p.Name)
+			}
+		}
+	}
+	preview.TimeEntries, preview.TimeEntriesError = r.countEntries(ctx, wsID, clockify.DetailedReportRequest{
+		Clients: &clockify.ReportClientFilter{IDs: []string{clientID}},
+	})
+	return preview, nil
+}
+
+func (r *registry) timeEntryDeletePreview(ctx context.Context, wsID, entryID string) (any, *mcp.CallToolResult) {
+	entry, err := r.client(ctx).GetTimeEntry(ctx, wsID, entryID)
+	if err != nil {
+		return nil, apiErrorResult("get time entry", err, "time entry "+entryID, wsID)
+	}
+	loc, _ := r.userLocation(ctx)
+	return timeEntryDeletePreview{Entry: localEntry(*entry, loc)}, nil
+}
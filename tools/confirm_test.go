@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// projectServer serves one project, p1, with two tasks and 42 time entries.
+type projectServer struct {
+	deletes int
+}
+
+func (f *projectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/projects/p1":
+		w.Write([]byte(`{"id":"p1","name":"Website Redesign","clientName":"Acme"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/projects/p1/tasks":
+		w.Write([]byte(`[{"id":"k1","name":"Design"},{"id":"k2","name":"Development"}]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/reports/workspaces/ws1/reports/detailed":
+		w.Write([]byte(`{"timeentries":[{"_id":"e1"}],"totals":[],"totalsCount":42}`))
+	case r.Method == http.MethodDelete && r.URL.Path == "/workspaces/ws1/projects/p1":
+		f.deletes++
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newConfirmRegistry(t *testing.T, fake http.Handler) *registry {
+	r := newTestRegistry(t, fake)
+	r.confirmDeletes = DefaultConfirmDeletes
+	r.confirmations = newConfirmations()
+	return r
+}
+
+func TestProjectDelete_NeedsConfirmation(t *testing.T) {
+	fake := &projectServer{}
+	r := newConfirmRegistry(t, fake)
+
+	result := callTool(t, projectDeleteHandler(r), map[string]any{"project_id": "p1"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if fake.deletes != 0 {
+		t.Fatal("project deleted without a confirmation token")
+	}
+	var out struct {
+		deleteConfirmation
+		Preview projectDeletePreview `json:"preview"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if !out.ConfirmationRequired || out.ConfirmToken == "" {
+		t.Fatalf("expected a confirmation token: %s", resultText(t, result))
+	}
+	if out.Preview.Project.Name != "Website Redesign" || out.Preview.Tasks != 2 {
+		t.Fatalf("unexpected preview: %+v", out.Preview)
+	}
+	if out.Preview.TimeEntries == nil || *out.Preview.TimeEntries != 42 {
+		t.Fatalf("expected 42 time entries, got %s", resultText(t, result))
+	}
+
+	// The token does not confirm another project.
+	result = callTool(t, projectDeleteHandler(r), map[string]any{"project_id": "p2", "confirm_token": out.ConfirmToken})
+	if !result.IsError {
+		t.Fatalf("token confirmed another project: %s", resultText(t, result))
+	}
+
+	result = callTool(t, projectDeleteHandler(r), map[string]any{"project_id": "p1", "confirm_token": out.ConfirmToken})
+	if result.IsError || fake.deletes != 1 {
+		t.Fatalf("expected the project to be deleted, got %s", resultText(t, result))
+	}
+
+	// Tokens are single use.
+	result = callTool(t, projectDeleteHandler(r), map[string]any{"project_id": "p1", "confirm_token": out.ConfirmToken})
+	if !result.IsError || fake.deletes != 1 {
+		t.Fatalf("token was accepted twice: %s", resultText(t, result))
+	}
+}
+
+func TestProjectDelete_ConfirmationTurnedOff(t *testing.T) {
+	fake := &projectServer{}
+	r := newConfirmRegistry(t, fake)
+	r.confirmDeletes = []string{}
+
+	result := callTool(t, projectDeleteHandler(r), map[string]any{"project_id": "p1"})
+	if result.IsError || fake.deletes != 1 {
+		t.Fatalf("expected an immediate delete, got %s", resultText(t, result))
+	}
+}
+
+func TestConfirmations_Expire(t *testing.T) {
+	c := newConfirmations()
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	p := pendingDelete{tool: "clockify_client_delete", wsID: "ws1", id: "c1"}
+
+	token, _ := c.issue(p)
+	now = now.Add(confirmTokenTTL)
+	if c.redeem(token, p) {
+		t.Fatal("expired token was accepted")
+	}
+}
+
+func TestCheckConfirmDeletes(t *testing.T) {
+	if err := CheckConfirmDeletes(confirmableDeletes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := CheckConfirmDeletes([]string{"clockify_tag_delete"})
+	if err == nil || !strings.Contains(err.Error(), "clockify_tag_delete") {
+		t.Fatalf("expected an error naming the tool, got %v", err)
+	}
+}
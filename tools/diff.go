@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"slices"
+	"time"
+
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// fieldChange is the value of one field before and after a write.
+type fieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// entryChanges lists the fields that differ between two versions of a time
+// entry, keyed by tool argument name. Times are rendered in loc.
+func entryChanges(before, after clockify.TimeEntry, loc *time.Location) map[string]fieldChange {
+	changes := map[string]fieldChange{}
+	diff := func(field string, from, to any, equal bool) {
+		if !equal {
+			changes[field] = fieldChange{From: from, To: to}
+		}
+	}
+
+	b, a := localEntry(before, loc), localEntry(after, loc)
+	diff("start", b.TimeInterval.Start, a.TimeInterval.Start, sameInstant(before.TimeInterval.Start, after.TimeInterval.Start))
+	diff("end", b.TimeInterval.End, a.TimeInterval.End, sameInstant(before.TimeInterval.End, after.TimeInterval.End))
+	diff("description", before.Description, after.Description, before.Description == after.Description)
+	diff("project_id", before.ProjectID, after.ProjectID, before.ProjectID == after.ProjectID)
+	diff("task_id", before.TaskID, after.TaskID, before.TaskID == after.TaskID)
+	diff("tag_ids", before.TagIDs, after.TagIDs, sameSet(before.TagIDs, after.TagIDs))
+	diff("billable", before.Billable, after.Billable, before.Billable == after.Billable)
+	return changes
+}
+
+// sameInstant compares two Clockify timestamps by the instant they denote.
+func sameInstant(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ta.Equal(tb)
+}
+
+// sameSet reports whether a and b hold the same IDs in any order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
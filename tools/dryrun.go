@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// dryRunOutput is what a tool that changes Clockify data returns instead of
+// calling the API: the clockify.Client method it would call, the IDs and
+// request it would pass, and, for updates and deletes, the entity as it
+// stands now.
+type dryRunOutput struct {
+	DryRun  bool              `json:"dry_run"`
+	Call    string            `json:"call"`
+	Args    map[string]string `json:"args"`
+	Request any               `json:"request,omitempty"`
+	Current any               `json:"current,omitempty"`
+	// NewTags are the tag names create_missing would create first. Their
+	// IDs are missing from the request, as they do not exist yet.
+	NewTags []string `json:"new_tags,omitempty"`
+}
+
+// dryRunArg adds the dry_run argument to a tool that changes Clockify data.
+func dryRunArg(tool *mcp.Tool) {
+	mcp.WithBoolean("dry_run",
+		mcp.Description("Validate the arguments and return the request that would be sent to Clockify, without sending it"),
+	)(tool)
+}
+
+// dryRunSchema is the output schema of a dry run.
+var dryRunSchema = func() mcp.ToolOutputSchema {
+	var tool mcp.Tool
+	mcp.WithOutputSchema[dryRunOutput]()(&tool)
+	return tool.OutputSchema
+}()
+
+// allowDryRunOutput widens the output schema of a tool that changes Clockify
+// data to accept either its result or a dry run, so both can be sent as
+// StructuredContent.
+func allowDryRunOutput(tool *mcp.Tool) {
+	raw, err := json.Marshal(map[string]any{
+		"type":  "object",
+		"anyOf": []mcp.ToolOutputSchema{tool.OutputSchema, dryRunSchema},
+	})
+	if err != nil {
+		return
+	}
+	tool.OutputSchema = mcp.ToolOutputSchema{}
+	tool.RawOutputSchema = raw
+}
+
+// isDryRun reports whether req must not change Clockify data, either
+// because it asks for a dry run or because the server runs in dry-run mode.
+func (r *registry) isDryRun(req mcp.CallToolRequest) bool {
+	return r.dryRun || req.GetBool("dry_run", false)
+}
+
+func dryRunResult(out dryRunOutput) (*mcp.CallToolResult, error) {
+	out.DryRun = true
+	return resultJSON(out)
+}
@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+func TestTimerStart_DryRunSendsNothing(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerStartHandler(r), map[string]any{
+		"project":        "website",
+		"tags":           []any{"meeting", "Urgent"},
+		"create_missing": true,
+		"dry_run":        true,
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if len(fake.createdTags) != 0 || len(fake.entryBodies) != 0 {
+		t.Fatalf("dry run wrote to Clockify: tags %v, entries %v", fake.createdTags, fake.entryBodies)
+	}
+
+	var out struct {
+		dryRunOutput
+		Request clockify.CreateTimeEntryRequest `json:"request"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if !out.DryRun || out.Call != "StartTimer" || out.Args["workspace_id"] != "ws1" {
+		t.Fatalf("unexpected dry run: %s", resultText(t, result))
+	}
+	if out.Request.ProjectID != "p1" || strings.Join(out.Request.TagIDs, ",") != "g1" || out.Request.Start == "" {
+		t.Fatalf("unexpected request: %+v", out.Request)
+	}
+	if strings.Join(out.NewTags, ",") != "Urgent" {
+		t.Fatalf("expected Urgent as a new tag, got %v", out.NewTags)
+	}
+}
+
+func TestTimeEntryUpdate_GlobalDryRunShowsCurrentState(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+	r.dryRun = true
+
+	// Asking for a real run does not override the server setting.
+	result := callTool(t, timeEntryUpdateHandler(r), map[string]any{"entry_id": "e1", "project": "internal", "dry_run": false})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if len(fake.puts) != 0 {
+		t.Fatalf("dry run sent %d PUTs", len(fake.puts))
+	}
+
+	var out struct {
+		dryRunOutput
+		Request clockify.UpdateTimeEntryRequest `json:"request"`
+		Current clockify.TimeEntry              `json:"current"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if out.Call != "UpdateTimeEntry" || out.Args["entry_id"] != "e1" {
+		t.Fatalf("unexpected dry run: %s", resultText(t, result))
+	}
+	if out.Request.ProjectID != "p2" || out.Request.TaskID != "" || out.Request.Description != "Standup" {
+		t.Fatalf("unexpected request: %+v", out.Request)
+	}
+	if out.Current.ProjectID != "p1" || out.Current.TaskID != "k1" {
+		t.Fatalf("unexpected current state: %+v", out.Current)
+	}
+}
+
+func TestTimeEntryDelete_DryRunReportsMissingEntry(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timeEntryDeleteHandler(r), map[string]any{"entry_id": "e2", "dry_run": true})
+	if !result.IsError {
+		t.Fatalf("expected an error for an unknown entry, got %s", resultText(t, result))
+	}
+}
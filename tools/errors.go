@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// apiErrorResult turns a failed Clockify call into a tool error an agent can
+// act on. action describes the attempted operation ("update project"),
+// subject names the entity the call targeted (e.g. "project abc") and may be
+// empty for calls that only address the workspace.
+func apiErrorResult(action string, err error, subject, wsID string) *mcp.CallToolResult {
+	return mcp.NewToolResultError(fmt.Sprintf("Failed to %s: %s", action, describeAPIError(err, subject, wsID)))
+}
+
+func describeAPIError(err error, subject, wsID string) string {
+	if errors.Is(err, context.Canceled) {
+		return "request was cancelled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "request timed out"
+	}
+
+	apiErr, ok := clockify.AsAPIError(err)
+	if !ok {
+		return err.Error()
+	}
+
+	where := ""
+	if wsID != "" {
+		where = " in workspace " + wsID
+	}
+
+	switch {
+	case clockify.IsNotFound(err):
+		if subject == "" && wsID == "" {
+			return fmt.Sprintf("not found: %s", apiErr.Message)
+		}
+		if subject == "" {
+			return fmt.Sprintf("workspace %s not found or not accessible", wsID)
+		}
+		return fmt.Sprintf("%s not found%s", subject, where)
+	case clockify.IsUnauthorized(err):
+		return "Clockify rejected the API key; check CLOCKIFY_API_KEY"
+	case clockify.IsForbidden(err):
+		if subject == "" {
+			return fmt.Sprintf("permission denied%s; the API key's user lacks access", where)
+		}
+		return fmt.Sprintf("permission denied for %s%s; the API key's user lacks access", subject, where)
+	case clockify.IsRateLimited(err):
+		return "Clockify rate limit exceeded, wait a moment and try again"
+	case clockify.IsValidation(err):
+		return fmt.Sprintf("Clockify rejected the request: %s", apiErr.Message)
+	default:
+		return fmt.Sprintf("Clockify API error (%d): %s", apiErr.StatusCode, apiErr.Message)
+	}
+}
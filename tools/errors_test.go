@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+func TestAPIErrorResult_MapsAPIErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		subject string
+		want    string
+	}{
+		{"not found", &clockify.APIError{StatusCode: 404, Message: "Not found"}, "project p1",
+			"Failed to update project: project p1 not found in workspace ws1"},
+		{"workspace not found", &clockify.APIError{StatusCode: 404}, "",
+			"Failed to update project: workspace ws1 not found or not accessible"},
+		{"forbidden", &clockify.APIError{StatusCode: 403}, "project p1",
+			"Failed to update project: permission denied for project p1 in workspace ws1; the API key's user lacks access"},
+		{"unauthorized", &clockify.APIError{StatusCode: 401}, "project p1",
+			"Failed to update project: Clockify rejected the API key; check CLOCKIFY_API_KEY"},
+		{"validation", &clockify.APIError{StatusCode: 400, Message: "Name is required"}, "project p1",
+			"Failed to update project: Clockify rejected the request: Name is required"},
+		{"rate limited", fmt.Errorf("wrapped: %w", &clockify.APIError{StatusCode: 429}), "project p1",
+			"Failed to update project: Clockify rate limit exceeded, wait a moment and try again"},
+		{"server error", &clockify.APIError{StatusCode: 500, Message: "boom"}, "project p1",
+			"Failed to update project: Clockify API error (500): boom"},
+		{"cancelled", fmt.Errorf("request failed: %w", context.Canceled), "project p1",
+			"Failed to update project: request was cancelled"},
+		{"other", fmt.Errorf("request failed: dial tcp: no route"), "project p1",
+			"Failed to update project: request failed: dial tcp: no route"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := apiErrorResult("update project", tt.err, tt.subject, "ws1")
+			if !result.IsError {
+				t.Fatal("expected IsError to be set")
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if text != tt.want {
+				t.Fatalf("got  %q\nwant %q", text, tt.want)
+			}
+			if strings.Contains(text, "{") {
+				t.Fatalf("error text should not contain raw JSON: %q", text)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolAllowed applies the read-only mode and the enabled and disabled tool
+// patterns to tool.
+func (r *registry) toolAllowed(tool mcp.Tool) bool {
+	if r.readOnly && changesClockify(tool) {
+		return false
+	}
+	if len(r.enabledTools) > 0 && !matchesAny(r.enabledTools, tool.Name) {
+		return false
+	}
+	return !matchesAny(r.disabledTools, tool.Name)
+}
+
+// changesClockify reports whether tool writes Clockify data, judging by its
+// annotations. Tools that only touch server state, such as the cache
+// refresh, are not open-world and stay available in read-only mode.
+func changesClockify(tool mcp.Tool) bool {
+	a := tool.Annotations
+	readOnly := a.ReadOnlyHint != nil && *a.ReadOnlyHint
+	openWorld := a.OpenWorldHint == nil || *a.OpenWorldHint
+	return !readOnly && openWorld
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckToolPatterns reports the first malformed glob pattern in patterns.
+func CheckToolPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
@@ -1,8 +1,16 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
 )
 
 func TestResultJSON_WireFormat(t *testing.T) {
@@ -74,3 +82,139 @@ func TestResultJSON_ErrorOnMarshalFailure(t *testing.T) {
 		t.Fatal("expected error for unmarshalable data, got nil")
 	}
 }
+
+// clientInfoSession is an MCP session that remembers the client's
+// initialize info.
+type clientInfoSession struct {
+	*fakeClientSession
+	info mcp.Implementation
+}
+
+func (c *clientInfoSession) GetClientInfo() mcp.Implementation     { return c.info }
+func (c *clientInfoSession) SetClientInfo(info mcp.Implementation) { c.info = info }
+func (c *clientInfoSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{}
+}
+func (c *clientInfoSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+
+// callToolWire calls a tool with args through a server registered with mode,
+// as the named client, and returns the JSON-RPC result as sent on the wire.
+func callToolWire(t *testing.T, mode StructuredMode, clientName, tool string, args map[string]any) map[string]any {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/workspaces":
+			w.Write([]byte(`[{"id":"ws1","name":"Acme"}]`))
+		case "/workspaces/ws1/user/u1/time-entries":
+			w.Write([]byte(`[]`))
+		case "/user":
+			w.Write([]byte(`{"id":"u1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client := clockify.NewClient("test-key",
+		clockify.WithBaseURL(srv.URL),
+		clockify.WithRetryPolicy(clockify.RetryPolicy{MaxAttempts: 1}),
+		clockify.WithRateLimit(clockify.RateLimit{}),
+	)
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(false))
+	RegisterAll(s, client, "ws1", Options{StructuredContent: mode})
+	if args == nil {
+		args = map[string]any{}
+	}
+
+	ctx := context.Background()
+	if clientName != "" {
+		session := &clientInfoSession{fakeClientSession: newFakeClientSession("s1"), info: mcp.Implementation{Name: clientName}}
+		ctx = s.WithContext(ctx, session)
+	}
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "tools/call",
+		"params": map[string]any{"name": tool, "arguments": args},
+	})
+	wireBytes, err := json.Marshal(s.HandleMessage(ctx, msg))
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	var wire struct {
+		Result map[string]any `json:"result"`
+	}
+	if err := json.Unmarshal(wireBytes, &wire); err != nil || wire.Result == nil {
+		t.Fatalf("unexpected response: %s", wireBytes)
+	}
+	return wire.Result
+}
+
+func TestToolResult_StructuredContentModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       StructuredMode
+		client     string
+		tool       string
+		structured bool
+	}{
+		{"off", StructuredOff, "inspector", "clockify_workspace_list", false},
+		{"on", StructuredOn, "claude-code", "clockify_workspace_list", true},
+		{"auto for a spec-compliant client", StructuredAuto, "inspector", "clockify_workspace_list", true},
+		{"auto for a client that rejects it", StructuredAuto, "claude-code", "clockify_workspace_list", false},
+		{"auto without client info", StructuredAuto, "", "clockify_workspace_list", false},
+		{"on with a plain-text result", StructuredOn, "inspector", "clockify_timer_current", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wire := callToolWire(t, tt.mode, tt.client, tt.tool, nil)
+
+			// The text content is always there for clients that ignore
+			// structuredContent.
+			content, ok := wire["content"].([]any)
+			if !ok || len(content) != 1 {
+				t.Fatalf("expected one content item, got %v", wire["content"])
+			}
+			text, _ := content[0].(map[string]any)["text"].(string)
+
+			structured, exists := wire["structuredContent"]
+			if exists != tt.structured {
+				t.Fatalf("structuredContent present = %v, want %v\nWire: %v", exists, tt.structured, wire)
+			}
+			if exists {
+				var fromText any
+				if err := json.Unmarshal([]byte(text), &fromText); err != nil {
+					t.Fatalf("content text is not valid JSON: %v", err)
+				}
+				a, _ := json.Marshal(fromText)
+				b, _ := json.Marshal(structured)
+				if string(a) != string(b) {
+					t.Fatalf("structuredContent %s differs from text %s", b, a)
+				}
+			}
+		})
+	}
+}
+
+func TestToolResult_DryRunStructuredContent(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0")
+	RegisterAll(s, nil, "", Options{})
+	schema, _ := json.Marshal(s.GetTool("clockify_tag_create").Tool)
+	var declared struct {
+		OutputSchema struct {
+			AnyOf []struct {
+				Required []string `json:"required"`
+			} `json:"anyOf"`
+		} `json:"outputSchema"`
+	}
+	json.Unmarshal(schema, &declared)
+	if len(declared.OutputSchema.AnyOf) != 2 || !slices.Contains(declared.OutputSchema.AnyOf[1].Required, "dry_run") {
+		t.Fatalf("output schema does not accept a dry run: %s", schema)
+	}
+
+	for _, mode := range []StructuredMode{StructuredOn, StructuredAuto} {
+		wire := callToolWire(t, mode, "inspector", "clockify_tag_create", map[string]any{"name": "Billable", "dry_run": true})
+		structured, ok := wire["structuredContent"].(map[string]any)
+		if !ok || structured["dry_run"] != true || structured["call"] != "CreateTag" {
+			t.Fatalf("mode %d: expected the dry run as structuredContent, got %v", mode, wire)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,10 +9,13 @@ import (
 )
 
 func registerProjectTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_project_list",
-			mcp.WithDescription("List projects in a workspace (paginated, default page 1, page_size 50)"),
+			mcp.WithDescription("List projects in a workspace (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List projects"),
+			mcp.WithOutputSchema[projectListOutput](),
 			mcp.WithBoolean("archived", mcp.Description("Include archived projects")),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of projects per page (default 50, max 5000)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -21,11 +23,14 @@ func registerProjectTools(s *server.MCPServer, r *registry) {
 		projectListHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_project_create",
 			mcp.WithDescription("Create a new project"),
+			createTool("Create project"),
+			mcp.WithOutputSchema[clockify.Project](),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Project name")),
 			mcp.WithString("client_id", mcp.Description("Client ID")),
+			mcp.WithString("client", mcp.Description("Client name, matched case-insensitively (alternative to client_id)")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the project is billable")),
 			mcp.WithString("color", mcp.Description("Project color (hex, e.g. #FF0000)")),
 			mcp.WithBoolean("is_public", mcp.Description("Whether the project is public")),
@@ -34,12 +39,15 @@ func registerProjectTools(s *server.MCPServer, r *registry) {
 		projectCreateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_project_update",
 			mcp.WithDescription("Update an existing project"),
+			updateTool("Update project"),
+			mcp.WithOutputSchema[clockify.Project](),
 			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID to update")),
 			mcp.WithString("name", mcp.Description("New project name")),
 			mcp.WithString("client_id", mcp.Description("Client ID")),
+			mcp.WithString("client", mcp.Description("Client name, matched case-insensitively (alternative to client_id)")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the project is billable")),
 			mcp.WithString("color", mcp.Description("Project color (hex)")),
 			mcp.WithBoolean("archived", mcp.Description("Whether the project is archived")),
@@ -48,9 +56,10 @@ func registerProjectTools(s *server.MCPServer, r *registry) {
 		projectUpdateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_project_delete",
 			mcp.WithDescription("Delete a project"),
+			deleteTool("Delete project"),
 			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID to delete")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
@@ -60,26 +69,34 @@ func registerProjectTools(s *server.MCPServer, r *registry) {
 
 func projectListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
+		if req.GetBool("all", false) {
+			list, err := r.allProjects(ctx, wsID, req.GetBool("archived", false))
+			if err != nil {
+				return apiErrorResult("list projects", err, "", wsID), nil
+			}
+			return resultJSON(projectListOutput{Projects: list.items, Truncated: list.truncated})
+		}
+
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		projects, err := r.client.GetProjects(wsID, req.GetBool("archived", false), page, pageSize)
+		projects, err := r.client(ctx).GetProjects(ctx, wsID, req.GetBool("archived", false), page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+			return apiErrorResult("list projects", err, "", wsID), nil
 		}
 
-		return resultJSON(map[string]any{"projects": projects})
+		return resultJSON(projectListOutput{Projects: projects})
 	}
 }
 
 func projectCreateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -89,16 +106,31 @@ func projectCreateHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("name is required"), nil
 		}
 
-		project, err := r.client.CreateProject(wsID, clockify.CreateProjectRequest{
+		clientID, err := r.clientArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+
+		createReq := clockify.CreateProjectRequest{
 			Name:     name,
-			ClientID: req.GetString("client_id", ""),
+			ClientID: clientID,
 			Billable: req.GetBool("billable", false),
 			Color:    req.GetString("color", ""),
 			IsPublic: req.GetBool("is_public", true),
-		})
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "CreateProject",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: createReq,
+			})
+		}
+
+		project, err := r.client(ctx).CreateProject(ctx, wsID, createReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create project: %v", err)), nil
+			return apiErrorResult("create project", err, "", wsID), nil
 		}
+		r.cache(ctx).invalidate(projectsKey(wsID))
 
 		return resultJSON(project)
 	}
@@ -106,7 +138,7 @@ func projectCreateHandler(r *registry) server.ToolHandlerFunc {
 
 func projectUpdateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -116,9 +148,14 @@ func projectUpdateHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("project_id is required"), nil
 		}
 
+		clientID, err := r.clientArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+
 		updateReq := clockify.UpdateProjectRequest{
 			Name:     req.GetString("name", ""),
-			ClientID: req.GetString("client_id", ""),
+			ClientID: clientID,
 			Color:    req.GetString("color", ""),
 		}
 
@@ -132,10 +169,24 @@ func projectUpdateHandler(r *registry) server.ToolHandlerFunc {
 			updateReq.Archived = &a
 		}
 
-		project, err := r.client.UpdateProject(wsID, projectID, updateReq)
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetProject(ctx, wsID, projectID)
+			if err != nil {
+				return apiErrorResult("get project", err, "project "+projectID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "UpdateProject",
+				Args:    map[string]string{"workspace_id": wsID, "project_id": projectID},
+				Request: updateReq,
+				Current: current,
+			})
+		}
+
+		project, err := r.client(ctx).UpdateProject(ctx, wsID, projectID, updateReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to update project: %v", err)), nil
+			return apiErrorResult("update project", err, "project "+projectID, wsID), nil
 		}
+		r.cache(ctx).invalidate(projectsKey(wsID))
 
 		return resultJSON(project)
 	}
@@ -143,7 +194,7 @@ func projectUpdateHandler(r *registry) server.ToolHandlerFunc {
 
 func projectDeleteHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -153,9 +204,28 @@ func projectDeleteHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("project_id is required"), nil
 		}
 
-		if err := r.client.DeleteProject(wsID, projectID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete project: %v", err)), nil
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetProject(ctx, wsID, projectID)
+			if err != nil {
+				return apiErrorResult("get project", err, "project "+projectID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "DeleteProject",
+				Args:    map[string]string{"workspace_id": wsID, "project_id": projectID},
+				Current: current,
+			})
+		}
+
+		if result := r.confirmDelete(ctx, req, "clockify_project_delete", wsID, projectID, func() (any, *mcp.CallToolResult) {
+			return r.projectDeletePreview(ctx, wsID, projectID)
+		}); result != nil {
+			return result, nil
+		}
+
+		if err := r.client(ctx).DeleteProject(ctx, wsID, projectID); err != nil {
+			return apiErrorResult("delete project", err, "project "+projectID, wsID), nil
 		}
+		r.cache(ctx).invalidate(projectsKey(wsID))
 
 		return mcp.NewToolResultText("Project deleted successfully."), nil
 	}
@@ -0,0 +1,519 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
+)
+
+// invoiceLineItemLimit caps the detailed report fetched for
+// client_invoice_prep; larger periods are marked truncated.
+const invoiceLineItemLimit = 1000
+
+func registerPrompts(s *server.MCPServer, r *registry) {
+	r.addPrompt(s,
+		mcp.NewPrompt("weekly_timesheet_review",
+			mcp.WithPromptDescription("Review a week of time entries for gaps, overlaps and misbooked entries"),
+			mcp.WithArgument("week", mcp.ArgumentDescription("Any day or period in the week, e.g. 'last week' or '2024-03-11' (default: this week)")),
+			mcp.WithArgument("user", mcp.ArgumentDescription("Workspace member's name or email (default: you)")),
+		),
+		weeklyTimesheetReviewPrompt(r),
+	)
+
+	r.addPrompt(s,
+		mcp.NewPrompt("fill_missing_days",
+			mcp.WithPromptDescription("Propose time entries for workdays with nothing tracked"),
+			mcp.WithArgument("week", mcp.ArgumentDescription("Any day or period in the week, e.g. 'last week' or '2024-03-11' (default: this week)")),
+			mcp.WithArgument("user", mcp.ArgumentDescription("Workspace member's name or email (default: you)")),
+		),
+		fillMissingDaysPrompt(r),
+	)
+
+	r.addPrompt(s,
+		mcp.NewPrompt("end_of_day_summary",
+			mcp.WithPromptDescription("Summarize a day's work by project"),
+			mcp.WithArgument("date", mcp.ArgumentDescription("Day to summarize, e.g. 'yesterday' or '2024-03-13' (default: today)")),
+			mcp.WithArgument("user", mcp.ArgumentDescription("Workspace member's name or email (default: you)")),
+		),
+		endOfDaySummaryPrompt(r),
+	)
+
+	r.addPrompt(s,
+		mcp.NewPrompt("client_invoice_prep",
+			mcp.WithPromptDescription("Prepare invoice line items for a client from a period's time report"),
+			mcp.WithArgument("client", mcp.RequiredArgument(), mcp.ArgumentDescription("Client name or ID")),
+			mcp.WithArgument("range", mcp.ArgumentDescription("Billing period, e.g. 'last month' or 'last 2 weeks' (default: last month)")),
+			mcp.WithArgument("user", mcp.ArgumentDescription("Only include this workspace member's time (name or email)")),
+		),
+		clientInvoicePrepPrompt(r),
+	)
+}
+
+// addPrompt registers a prompt whose handler runs in the caller's session.
+func (r *registry) addPrompt(s *server.MCPServer, prompt mcp.Prompt, handler server.PromptHandlerFunc) {
+	s.AddPrompt(prompt, func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx, err := r.sessionContext(ctx)
+		if err != nil {
+			return nil, errors.New(describeSessionError(err))
+		}
+		return handler(ctx, req)
+	})
+}
+
+// promptArg returns the named prompt argument, or def when it is empty.
+func promptArg(req mcp.GetPromptRequest, name, def string) string {
+	if v := strings.TrimSpace(req.Params.Arguments[name]); v != "" {
+		return v
+	}
+	return def
+}
+
+// promptError describes a failed lookup made to build a prompt. Name
+// resolution failures are structured JSON, as for tools.
+func promptError(action string, err error, wsID string) error {
+	var re *resolveError
+	if errors.As(err, &re) {
+		b, _ := json.Marshal(re)
+		return errors.New(string(b))
+	}
+	return fmt.Errorf("failed to %s: %s", action, describeAPIError(err, "", wsID))
+}
+
+// promptResult builds a single user message from instructions followed by
+// data as a JSON block, so the conversation starts from live Clockify data.
+func promptResult(description, instructions string, data any) (*mcp.GetPromptResult, error) {
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal prompt data: %w", err)
+	}
+	text := instructions + "\n\n```json\n" + string(b) + "\n```"
+	return mcp.NewGetPromptResult(description, []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(text)),
+	}), nil
+}
+
+// promptUser resolves the user argument to a workspace member by ID, email
+// or name. Empty means the current user.
+func (r *registry) promptUser(ctx context.Context, wsID, query string) (*clockify.User, error) {
+	if query == "" {
+		return r.currentUser(ctx)
+	}
+	users, _, err := clockify.Collect(r.client(ctx).AllWorkspaceUsers(ctx, wsID))
+	if err != nil {
+		return nil, err
+	}
+	cands := make([]candidate, len(users))
+	for i, u := range users {
+		if u.ID == query || strings.EqualFold(u.Email, query) {
+			return &users[i], nil
+		}
+		cands[i] = candidate{ID: u.ID, Name: u.Name}
+	}
+	match, err := resolveName("user", query, cands)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(users, func(u clockify.User) bool { return u.ID == match.ID })
+	return &users[i], nil
+}
+
+// weekOf returns the Monday-to-Monday week containing the start of the
+// period expr.
+func weekOf(p timeexpr.Parser, expr string) (start, end time.Time, err error) {
+	s, _, err := p.Range(expr)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid week: %w", err)
+	}
+	day := startOfDay(s, p.Location)
+	start = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return start, start.AddDate(0, 0, 7), nil
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// promptEntry is a time entry as shown in prompts: local times, hours and
+// the project by name.
+type promptEntry struct {
+	ID          string  `json:"id"`
+	Start       string  `json:"start"`
+	End         string  `json:"end,omitempty"`
+	Hours       float64 `json:"hours"`
+	Description string  `json:"description,omitempty"`
+	Project     string  `json:"project,omitempty"`
+	Billable    bool    `json:"billable"`
+	Running     bool    `json:"running,omitempty"`
+}
+
+// dayTotal is the time tracked on one day.
+type dayTotal struct {
+	Date    string  `json:"date"`
+	Weekday string  `json:"weekday"`
+	Hours   float64 `json:"hours"`
+	Entries int     `json:"entries"`
+
+	weekday time.Weekday
+}
+
+// projectTotal is the time tracked on one project.
+type projectTotal struct {
+	Project string  `json:"project"`
+	Hours   float64 `json:"hours"`
+}
+
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
+
+// entryDuration is the length of e; a running entry counts until now.
+func entryDuration(e clockify.TimeEntry, now time.Time) time.Duration {
+	start, err := time.Parse(time.RFC3339, e.TimeInterval.Start)
+	if err != nil {
+		return 0
+	}
+	end := now
+	if e.TimeInterval.End != "" {
+		if end, err = time.Parse(time.RFC3339, e.TimeInterval.End); err != nil {
+			return 0
+		}
+	}
+	return end.Sub(start)
+}
+
+// entriesBetween fetches userID's time entries that start in [start, end).
+func (r *registry) entriesBetween(ctx context.Context, wsID, userID string, start, end time.Time) ([]clockify.TimeEntry, bool, error) {
+	params := url.Values{"start": {timeexpr.Format(start)}, "end": {timeexpr.Format(end)}}
+	return clockify.Collect(r.client(ctx).AllTimeEntries(ctx, wsID, userID, params))
+}
+
+// projectNames maps project IDs to names for rendering entries.
+func (r *registry) projectNames(ctx context.Context, wsID string) (map[string]string, error) {
+	projects, err := r.allProjects(ctx, wsID, false)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(projects.items))
+	for _, p := range projects.items {
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
+// timesheet is a user's time entries over a span of days.
+type timesheet struct {
+	User       string         `json:"user"`
+	Timezone   string         `json:"timezone"`
+	From       string         `json:"from"`
+	To         string         `json:"to"`
+	TotalHours float64        `json:"total_hours"`
+	Days       []dayTotal     `json:"days"`
+	Projects   []projectTotal `json:"projects"`
+	Entries    []promptEntry  `json:"entries"`
+	Truncated  bool           `json:"truncated,omitempty"`
+}
+
+// loadTimesheet fetches the entries of user over [start, end) and totals
+// them by day and project.
+func (r *registry) loadTimesheet(ctx context.Context, wsID string, user *clockify.User, start, end time.Time, loc *time.Location) (*timesheet, error) {
+	entries, truncated, err := r.entriesBetween(ctx, wsID, user.ID, start, end)
+	if err != nil {
+		return nil, promptError("list time entries", err, wsID)
+	}
+	names, err := r.projectNames(ctx, wsID)
+	if err != nil {
+		return nil, promptError("list projects", err, wsID)
+	}
+
+	ts := &timesheet{
+		User:      user.Name,
+		Timezone:  loc.String(),
+		From:      start.Format(time.DateOnly),
+		To:        end.AddDate(0, 0, -1).Format(time.DateOnly),
+		Days:      []dayTotal{},
+		Projects:  []projectTotal{},
+		Entries:   make([]promptEntry, 0, len(entries)),
+		Truncated: truncated,
+	}
+	byDay := map[string]time.Duration{}
+	countByDay := map[string]int{}
+	byProject := map[string]time.Duration{}
+	var total time.Duration
+	now := time.Now()
+
+	slices.SortFunc(entries, func(a, b clockify.TimeEntry) int {
+		return cmp.Compare(a.TimeInterval.Start, b.TimeInterval.Start)
+	})
+	for _, e := range entries {
+		d := entryDuration(e, now)
+		project := cmp.Or(names[e.ProjectID], e.ProjectID)
+		local := localEntry(e, loc)
+		ts.Entries = append(ts.Entries, promptEntry{
+			ID:          e.ID,
+			Start:       local.TimeInterval.Start,
+			End:         local.TimeInterval.End,
+			Hours:       hours(d),
+			Description: e.Description,
+			Project:     project,
+			Billable:    e.Billable,
+			Running:     e.TimeInterval.End == "",
+		})
+		day := e.TimeInterval.Start
+		if t, err := time.Parse(time.RFC3339, day); err == nil {
+			day = t.In(loc).Format(time.DateOnly)
+		}
+		byDay[day] += d
+		countByDay[day]++
+		byProject[cmp.Or(project, "(no project)")] += d
+		total += d
+	}
+
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		ts.Days = append(ts.Days, dayTotal{
+			Date:    date,
+			Weekday: day.Weekday().String(),
+			Hours:   hours(byDay[date]),
+			Entries: countByDay[date],
+			weekday: day.Weekday(),
+		})
+	}
+	for project, d := range byProject {
+		ts.Projects = append(ts.Projects, projectTotal{Project: project, Hours: hours(d)})
+	}
+	slices.SortFunc(ts.Projects, func(a, b projectTotal) int {
+		return cmp.Or(cmp.Compare(b.Hours, a.Hours), cmp.Compare(a.Project, b.Project))
+	})
+	ts.TotalHours = hours(total)
+	return ts, nil
+}
+
+// weekTimesheet resolves the week and user arguments and loads that
+// timesheet.
+func (r *registry) weekTimesheet(ctx context.Context, req mcp.GetPromptRequest) (*timesheet, *time.Location, error) {
+	wsID := r.workspaceID(ctx, "")
+	p, err := r.parser(ctx)
+	if err != nil {
+		return nil, nil, promptError("get current user", err, "")
+	}
+	start, end, err := weekOf(p, promptArg(req, "week", "this week"))
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := r.promptUser(ctx, wsID, promptArg(req, "user", ""))
+	if err != nil {
+		return nil, nil, promptError("look up user", err, wsID)
+	}
+	ts, err := r.loadTimesheet(ctx, wsID, user, start, end, p.Location)
+	return ts, p.Location, err
+}
+
+func weeklyTimesheetReviewPrompt(r *registry) server.PromptHandlerFunc {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ts, _, err := r.weekTimesheet(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		instructions := fmt.Sprintf("Review %s's Clockify timesheet for the week of %s to %s, shown below. "+
+			"Point out days with missing or unusually long hours, overlapping or duplicate entries, and entries without a project or description. "+
+			"Suggest concrete fixes, and ask before changing anything with the Clockify tools.",
+			ts.User, ts.From, ts.To)
+		return promptResult("Weekly timesheet review for "+ts.User, instructions, ts)
+	}
+}
+
+func fillMissingDaysPrompt(r *registry) server.PromptHandlerFunc {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ts, loc, err := r.weekTimesheet(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only past and current workdays can be missing.
+		today := time.Now().In(loc).Format(time.DateOnly)
+		missing := []string{}
+		for _, d := range ts.Days {
+			if d.Entries == 0 && d.Date <= today && d.weekday != time.Saturday && d.weekday != time.Sunday {
+				missing = append(missing, d.Date)
+			}
+		}
+
+		data := map[string]any{"missing_days": missing, "timesheet": ts}
+		if len(missing) == 0 {
+			instructions := fmt.Sprintf("%s has time tracked on every workday so far in the week of %s to %s. Confirm this, and mention any day that looks short.",
+				ts.User, ts.From, ts.To)
+			return promptResult("No missing days for "+ts.User, instructions, data)
+		}
+		instructions := fmt.Sprintf("%s has nothing tracked in Clockify on these workdays in the week of %s to %s: %s. "+
+			"Using the rest of the week's entries below as a guide, propose time entries to fill them, with start, end, description and project for each. "+
+			"Show the proposal first and create the entries with clockify_time_entry_create only after confirmation.",
+			ts.User, ts.From, ts.To, strings.Join(missing, ", "))
+		return promptResult("Fill missing days for "+ts.User, instructions, data)
+	}
+}
+
+func endOfDaySummaryPrompt(r *registry) server.PromptHandlerFunc {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		wsID := r.workspaceID(ctx, "")
+		p, err := r.parser(ctx)
+		if err != nil {
+			return nil, promptError("get current user", err, "")
+		}
+		s, _, err := p.Range(promptArg(req, "date", "today"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date: %w", err)
+		}
+		user, err := r.promptUser(ctx, wsID, promptArg(req, "user", ""))
+		if err != nil {
+			return nil, promptError("look up user", err, wsID)
+		}
+		day := startOfDay(s, p.Location)
+		ts, err := r.loadTimesheet(ctx, wsID, user, day, day.AddDate(0, 0, 1), p.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		instructions := fmt.Sprintf("Write a short end-of-day summary of %s's work on %s from the Clockify entries below: "+
+			"what was done, grouped by project, with hours and the day's total. "+
+			"Mention a timer that is still running and any gap of more than an hour between entries.",
+			ts.User, ts.From)
+		return promptResult("End of day summary for "+ts.User, instructions, ts)
+	}
+}
+
+// invoiceLineItem is one detailed report entry in client_invoice_prep.
+type invoiceLineItem struct {
+	Date        string  `json:"date"`
+	Project     string  `json:"project"`
+	Description string  `json:"description,omitempty"`
+	User        string  `json:"user"`
+	Hours       float64 `json:"hours"`
+}
+
+func clientInvoicePrepPrompt(r *registry) server.PromptHandlerFunc {
+	return func(ctx context.Context, req mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		wsID := r.workspaceID(ctx, "")
+		clientArg := promptArg(req, "client", "")
+		if clientArg == "" {
+			return nil, errors.New("client is required")
+		}
+		p, err := r.parser(ctx)
+		if err != nil {
+			return nil, promptError("get current user", err, "")
+		}
+		start, end, err := p.Range(promptArg(req, "range", "last month"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid range: %w", err)
+		}
+
+		client, err := r.promptClient(ctx, wsID, clientArg)
+		if err != nil {
+			return nil, promptError("look up client", err, wsID)
+		}
+		var users *clockify.ReportUsersFilter
+		if q := promptArg(req, "user", ""); q != "" {
+			user, err := r.promptUser(ctx, wsID, q)
+			if err != nil {
+				return nil, promptError("look up user", err, wsID)
+			}
+			users = &clockify.ReportUsersFilter{IDs: []string{user.ID}}
+		}
+
+		clients := &clockify.ReportClientFilter{IDs: []string{client.ID}}
+		summary, err := r.client(ctx).GetSummaryReport(ctx, wsID, clockify.SummaryReportRequest{
+			DateRangeStart: timeexpr.Format(start),
+			DateRangeEnd:   inclusiveReportEnd(end),
+			TimeZone:       p.Location.String(),
+			SummaryFilter:  &clockify.SummaryFilter{Groups: []string{"PROJECT"}},
+			Clients:        clients,
+			Users:          users,
+		})
+		if err != nil {
+			return nil, promptError("get summary report", err, wsID)
+		}
+		detailed, err := r.client(ctx).GetDetailedReport(ctx, wsID, clockify.DetailedReportRequest{
+			DateRangeStart: timeexpr.Format(start),
+			DateRangeEnd:   inclusiveReportEnd(end),
+			TimeZone:       p.Location.String(),
+			Page:           1,
+			PageSize:       invoiceLineItemLimit,
+			DetailedFilter: &clockify.DetailedFilter{Page: 1, PageSize: invoiceLineItemLimit},
+			Clients:        clients,
+			Users:          users,
+		})
+		if err != nil {
+			return nil, promptError("get detailed report", err, wsID)
+		}
+
+		totals := map[string]float64{}
+		if len(summary.Totals) > 0 {
+			t := summary.Totals[0]
+			totals["hours"] = hours(time.Duration(t.TotalTime) * time.Second)
+			totals["billable_hours"] = hours(time.Duration(t.TotalBillable) * time.Second)
+			totals["amount"] = t.TotalAmount
+		}
+		projects := make([]projectTotal, len(summary.GroupOne))
+		for i, g := range summary.GroupOne {
+			projects[i] = projectTotal{Project: g.Name, Hours: hours(time.Duration(g.Duration) * time.Second)}
+		}
+		items := make([]invoiceLineItem, len(detailed.TimeEntries))
+		for i, e := range detailed.TimeEntries {
+			items[i] = invoiceLineItem{
+				Date:        localTime(e.TimeInterval.Start, p.Location),
+				Project:     e.ProjectName,
+				Description: e.Description,
+				User:        e.UserName,
+				Hours:       hours(time.Duration(cmp.Or(e.TimeInterval.Duration, e.Duration)) * time.Second),
+			}
+			if len(items[i].Date) >= len(time.DateOnly) {
+				items[i].Date = items[i].Date[:len(time.DateOnly)]
+			}
+		}
+
+		from, to := start.Format(time.DateOnly), end.AddDate(0, 0, -1).Format(time.DateOnly)
+		data := map[string]any{
+			"client":     client.Name,
+			"from":       from,
+			"to":         to,
+			"timezone":   p.Location.String(),
+			"totals":     totals,
+			"projects":   projects,
+			"line_items": items,
+			"truncated":  detailed.TotalCount > len(items),
+		}
+		instructions := fmt.Sprintf("Prepare an invoice for %s covering %s to %s from the Clockify report below. "+
+			"List the billable work grouped by project with hours, merge line items that describe the same work, "+
+			"and flag entries without a description or with unusual durations that should be checked before invoicing.",
+			client.Name, from, to)
+		return promptResult("Invoice preparation for "+client.Name, instructions, data)
+	}
+}
+
+// promptClient resolves a client argument by ID or name.
+func (r *registry) promptClient(ctx context.Context, wsID, query string) (candidate, error) {
+	clients, err := r.allClients(ctx, wsID)
+	if err != nil {
+		return candidate{}, err
+	}
+	cands := make([]candidate, len(clients.items))
+	for i, c := range clients.items {
+		if c.ID == query {
+			return candidate{ID: c.ID, Name: c.Name}, nil
+		}
+		cands[i] = candidate{ID: c.ID, Name: c.Name}
+	}
+	return resolveName("client", query, cands)
+}
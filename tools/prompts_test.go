@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// getPrompt sends a prompts/get request through s and returns the text of
+// the first message, or the JSON-RPC error message.
+func getPrompt(t *testing.T, s *server.MCPServer, name string, args map[string]string) (text string, errMsg string) {
+	t.Helper()
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "prompts/get",
+		"params": map[string]any{"name": name, "arguments": args},
+	})
+	resp := s.HandleMessage(context.Background(), msg)
+
+	switch resp := resp.(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(mcp.GetPromptResult)
+		if !ok || len(result.Messages) == 0 {
+			t.Fatalf("unexpected result: %#v", resp.Result)
+		}
+		return result.Messages[0].Content.(mcp.TextContent).Text, ""
+	case mcp.JSONRPCError:
+		return "", resp.Error.Message
+	default:
+		t.Fatalf("unexpected response: %#v", resp)
+		return "", ""
+	}
+}
+
+// promptWorkspace serves a workspace with two members and a few entries in
+// the week of Monday 2024-03-11, recording what was asked for.
+type promptWorkspace struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (f *promptWorkspace) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests = append(f.requests, r.URL.Path+"?"+r.URL.RawQuery+" "+string(body))
+	f.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/user":
+		w.Write([]byte(`{"id":"u1","name":"Ada"}`))
+	case "/workspaces/ws1/users":
+		w.Write([]byte(`[{"id":"u1","name":"Ada","email":"ada@example.com"},{"id":"u2","name":"Grace Hopper","email":"grace@example.com"}]`))
+	case "/workspaces/ws1/projects":
+		w.Write([]byte(`[{"id":"p1","name":"Website"}]`))
+	case "/workspaces/ws1/clients":
+		w.Write([]byte(`[{"id":"c1","name":"Globex"}]`))
+	case "/workspaces/ws1/user/u1/time-entries", "/workspaces/ws1/user/u2/time-entries":
+		if r.URL.Query().Get("page") != "1" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[
+			{"id":"e1","description":"Coding","projectId":"p1","timeInterval":{"start":"2024-03-11T09:00:00Z","end":"2024-03-11T12:30:00Z"}},
+			{"id":"e2","description":"Review","projectId":"p1","timeInterval":{"start":"2024-03-13T13:00:00Z","end":"2024-03-13T15:00:00Z"}}
+		]`))
+	case "/reports/workspaces/ws1/reports/summary":
+		w.Write([]byte(`{"totals":[{"totalTime":19800,"totalBillableTime":18000,"totalAmount":550}],"groupOne":[{"name":"Website","duration":19800}]}`))
+	case "/reports/workspaces/ws1/reports/detailed":
+		w.Write([]byte(`{"timeentries":[{"description":"Coding","projectName":"Website","userName":"Ada","timeInterval":{"start":"2024-02-05T09:00:00Z","duration":19800}}],"totalsCount":1}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *promptWorkspace) requested(substr string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.requests {
+		if strings.Contains(r, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func newPromptServer(t *testing.T) (*server.MCPServer, *promptWorkspace) {
+	t.Helper()
+	fake := &promptWorkspace{}
+	r := newTestRegistry(t, fake)
+	s := server.NewMCPServer("test", "1.0", server.WithPromptCapabilities(false))
+	registerPrompts(s, r)
+	return s, fake
+}
+
+func TestPrompts_WeeklyTimesheetReview(t *testing.T) {
+	s, fake := newPromptServer(t)
+	text, errMsg := getPrompt(t, s, "weekly_timesheet_review", map[string]string{"week": "2024-03-13", "user": "grace"})
+	if errMsg != "" {
+		t.Fatal(errMsg)
+	}
+
+	if !fake.requested("/workspaces/ws1/user/u2/time-entries?end=2024-03-18T00%3A00%3A00Z") ||
+		!fake.requested("start=2024-03-11T00%3A00%3A00Z") {
+		t.Fatalf("expected Grace's entries for the week of 2024-03-11, got %v", fake.requests)
+	}
+	for _, want := range []string{
+		"Grace Hopper's Clockify timesheet for the week of 2024-03-11 to 2024-03-17",
+		`"total_hours": 5.5`,
+		`"project": "Website"`,
+		`"date": "2024-03-11",`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPrompts_FillMissingDays(t *testing.T) {
+	s, _ := newPromptServer(t)
+	text, errMsg := getPrompt(t, s, "fill_missing_days", map[string]string{"week": "2024-03-11"})
+	if errMsg != "" {
+		t.Fatal(errMsg)
+	}
+	if !strings.Contains(text, "these workdays in the week of 2024-03-11 to 2024-03-17: 2024-03-12, 2024-03-14, 2024-03-15.") {
+		t.Fatalf("expected the missing weekdays to be listed:\n%s", text)
+	}
+}
+
+func TestPrompts_ClientInvoicePrep(t *testing.T) {
+	s, fake := newPromptServer(t)
+	text, errMsg := getPrompt(t, s, "client_invoice_prep", map[string]string{"client": "globex", "range": "2024-02-05"})
+	if errMsg != "" {
+		t.Fatal(errMsg)
+	}
+	if !fake.requested(`"clients":{"ids":["c1"]}`) {
+		t.Fatalf("expected reports filtered by client c1, got %v", fake.requests)
+	}
+	for _, want := range []string{"Prepare an invoice for Globex", `"billable_hours": 5`, `"date": "2024-02-05"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("prompt is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestPrompts_ReportErrors(t *testing.T) {
+	s, _ := newPromptServer(t)
+	for _, tt := range []struct {
+		name string
+		args map[string]string
+		want string
+	}{
+		{"client_invoice_prep", map[string]string{}, "client is required"},
+		{"client_invoice_prep", map[string]string{"client": "Initech"}, `"error":"not found"`},
+		{"client_invoice_prep", map[string]string{"client": "Globex", "range": "someday"}, "invalid range"},
+		{"weekly_timesheet_review", map[string]string{"user": "nobody"}, `"kind":"user"`},
+		{"end_of_day_summary", map[string]string{"date": "someday"}, "invalid date"},
+	} {
+		_, errMsg := getPrompt(t, s, tt.name, tt.args)
+		if !strings.Contains(errMsg, tt.want) {
+			t.Errorf("%s %v: got error %q, want %q", tt.name, tt.args, errMsg, tt.want)
+		}
+	}
+}
@@ -1,17 +1,21 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/audit"
 	"github.com/tedyno/ticktock-mcp/clockify"
 )
 
 // resultJSON marshals data to JSON and returns it as a text-only tool result.
 // This does NOT set StructuredContent, avoiding Claude Code's Zod validation
-// error on the structuredContent field.
+// error on the structuredContent field. Options.StructuredContent opts other
+// clients in (see withStructuredContent).
 func resultJSON(data any) (*mcp.CallToolResult, error) {
 	b, err := json.Marshal(data)
 	if err != nil {
@@ -20,9 +24,86 @@ func resultJSON(data any) (*mcp.CallToolResult, error) {
 	return mcp.NewToolResultText(string(b)), nil
 }
 
-// RegisterAll registers all Clockify MCP tools on the given server.
-func RegisterAll(s *server.MCPServer, client *clockify.Client, defaultWorkspaceID string) {
-	r := &registry{client: client, defaultWorkspaceID: defaultWorkspaceID}
+// Options tunes tool behavior. The zero value is ready to use.
+type Options struct {
+	// CacheTTL is how long current user, workspace, project, tag and client
+	// metadata is reused. Zero uses a 5 minute default; negative disables
+	// caching.
+	CacheTTL time.Duration
+
+	// Location overrides the timezone from the Clockify user settings for
+	// interpreting and rendering times. Nil uses the user's timezone.
+	Location *time.Location
+
+	// NewClient, when set, lets callers supply their own Clockify API key
+	// through WithAPIKey. Each key gets its own client built by NewClient,
+	// its own default workspace and its own cache.
+	NewClient func(apiKey string) *clockify.Client
+
+	// ServerKeyFallback lets callers that send no key of their own act with
+	// the server's key when NewClient is set. Without it they are refused.
+	ServerKeyFallback bool
+
+	// StructuredContent selects which clients get StructuredContent alongside
+	// the JSON text of tools with an output schema. The zero value sends
+	// text only.
+	StructuredContent StructuredMode
+
+	// ReadOnly leaves out every tool that changes Clockify data.
+	ReadOnly bool
+
+	// EnabledTools and DisabledTools filter tools by name with path.Match
+	// glob patterns such as "clockify_project_*". With EnabledTools set, only
+	// matching tools are registered; DisabledTools then removes matches.
+	EnabledTools  []string
+	DisabledTools []string
+
+	// DryRun makes every tool that changes Clockify data validate its
+	// arguments and return the request it would send, without sending it.
+	// Without it, callers can still ask for a dry run per call.
+	DryRun bool
+
+	// Journal, when set, records every call that changes Clockify data and
+	// enables the clockify_audit_query and clockify_undo tools.
+	Journal *audit.Journal
+
+	// ConfirmDeletes names the delete tools that first return a preview and
+	// a short-lived token, and only delete when called again with it. Nil
+	// means DefaultConfirmDeletes; an empty list turns confirmation off.
+	ConfirmDeletes []string
+
+	// TimerPollInterval is how often the running timer is checked for
+	// resource subscribers. Zero uses a 30 second default.
+	TimerPollInterval time.Duration
+}
+
+// RegisterAll registers all Clockify MCP tools, resources and prompts on the
+// given server. client may be nil when every caller supplies their own key
+// (see Options.NewClient).
+// The returned Subscriptions must be wired into the transport and run for
+// resource subscriptions to work.
+func RegisterAll(s *server.MCPServer, client *clockify.Client, defaultWorkspaceID string, opts Options) *Subscriptions {
+	r := &registry{
+		location:       opts.Location,
+		keyFallback:    opts.ServerKeyFallback,
+		structured:     opts.StructuredContent,
+		readOnly:       opts.ReadOnly,
+		dryRun:         opts.DryRun,
+		journal:        opts.Journal,
+		confirmDeletes: opts.ConfirmDeletes,
+		confirmations:  newConfirmations(),
+		enabledTools:   opts.EnabledTools,
+		disabledTools:  opts.DisabledTools,
+	}
+	if r.confirmDeletes == nil {
+		r.confirmDeletes = DefaultConfirmDeletes
+	}
+	if client != nil {
+		r.base = &session{client: client, defaultWorkspaceID: defaultWorkspaceID, cache: newTTLCache(opts.CacheTTL)}
+	}
+	if opts.NewClient != nil {
+		r.pool = newSessionPool(opts.NewClient, opts.CacheTTL)
+	}
 
 	registerTimerTools(s, r)
 	registerTimeEntryTools(s, r)
@@ -33,17 +114,36 @@ func RegisterAll(s *server.MCPServer, client *clockify.Client, defaultWorkspaceI
 	registerWorkspaceTools(s, r)
 	registerUserTools(s, r)
 	registerReportTools(s, r)
+	registerCacheTools(s, r)
+	registerAuditTools(s, r)
+	registerUndoTools(s, r)
+	registerResources(s, r)
+	registerPrompts(s, r)
+	return newSubscriptions(s, r, opts.TimerPollInterval)
 }
 
 type registry struct {
-	client             *clockify.Client
-	defaultWorkspaceID string
+	base        *session     // the server's own API key; nil if it has none
+	pool        *sessionPool // per-caller API keys; nil unless enabled
+	keyFallback bool         // keyless callers use base even with a pool
+	location    *time.Location
+	structured  StructuredMode
+
+	readOnly      bool
+	dryRun        bool
+	journal       *audit.Journal
+	enabledTools  []string
+	disabledTools []string
+
+	confirmDeletes []string
+	confirmations  *confirmations
 }
 
-// workspaceID returns the provided workspace ID or falls back to default.
-func (r *registry) workspaceID(override string) string {
+// workspaceID returns the provided workspace ID or falls back to the
+// caller's default.
+func (r *registry) workspaceID(ctx context.Context, override string) string {
 	if override != "" {
 		return override
 	}
-	return r.defaultWorkspaceID
+	return r.session(ctx).defaultWorkspaceID
 }
@@ -1,13 +1,19 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
 )
 
 func TestResultJSON_ReturnsTextContentWithJSON(t *testing.T) {
@@ -90,3 +96,149 @@ func TestNoToolUsesNewToolResultJSON(t *testing.T) {
 		}
 	}
 }
+
+// registeredTools returns the tools RegisterAll exposes with opts.
+func registeredTools(t *testing.T, opts Options) map[string]*server.ServerTool {
+	t.Helper()
+	s := server.NewMCPServer("test", "1.0", server.WithToolCapabilities(false))
+	RegisterAll(s, clockify.NewClient("test-key"), "ws1", opts)
+	return s.ListTools()
+}
+
+func TestAllToolsAreAnnotated(t *testing.T) {
+	// A journal adds the audit tools to the set checked.
+	tools := registeredTools(t, Options{Journal: newTestJournal(t)})
+	if len(tools) == 0 {
+		t.Fatal("no tools registered")
+	}
+	for name, tool := range tools {
+		a := tool.Tool.Annotations
+		// mcp.NewTool fills in hints but never a title, so an empty title
+		// means the tool was registered without annotations.
+		if a.Title == "" {
+			t.Errorf("%s has no annotations", name)
+			continue
+		}
+		readOnly := *a.ReadOnlyHint
+		switch {
+		case strings.HasSuffix(name, "_list") || strings.HasSuffix(name, "_current") || strings.Contains(name, "_report_"):
+			if !readOnly || *a.DestructiveHint {
+				t.Errorf("%s only reads but is not annotated read-only", name)
+			}
+		case strings.HasSuffix(name, "_delete"):
+			if readOnly || !*a.DestructiveHint {
+				t.Errorf("%s deletes but is not annotated destructive", name)
+			}
+		case strings.HasSuffix(name, "_create") || strings.HasSuffix(name, "_update"):
+			if readOnly {
+				t.Errorf("%s writes but is annotated read-only", name)
+			}
+		}
+
+		// Delete tools confirm in plain text, as does clockify_timer_current
+		// with no timer running; everything else returns JSON and must
+		// describe it.
+		if !strings.HasSuffix(name, "_delete") && name != "clockify_timer_current" && !hasOutputSchema(tool.Tool) {
+			t.Errorf("%s has no output schema", name)
+		}
+	}
+}
+
+func TestDryRunArgOnWritingToolsOnly(t *testing.T) {
+	for name, tool := range registeredTools(t, Options{Journal: newTestJournal(t)}) {
+		_, has := tool.Tool.InputSchema.Properties["dry_run"]
+		if want := changesClockify(tool.Tool); has != want {
+			t.Errorf("%s has dry_run = %v, want %v", name, has, want)
+		}
+	}
+}
+
+// timerWriteTools are the timer tools that change Clockify data.
+var timerWriteTools = []string{"clockify_timer_start", "clockify_timer_stop", "clockify_timer_switch", "clockify_timer_resume"}
+
+func TestRegisterAll_FiltersTools(t *testing.T) {
+	all := registeredTools(t, Options{})
+	tests := []struct {
+		name string
+		opts Options
+		want func(name string) bool
+	}{
+		{"read only", Options{ReadOnly: true}, func(name string) bool {
+			return !strings.HasSuffix(name, "_create") && !strings.HasSuffix(name, "_update") &&
+				!strings.HasSuffix(name, "_delete") && !slices.Contains(timerWriteTools, name)
+		}},
+		{"enabled", Options{EnabledTools: []string{"clockify_project_*", "clockify_user_current"}}, func(name string) bool {
+			return strings.HasPrefix(name, "clockify_project_") || name == "clockify_user_current"
+		}},
+		{"disabled", Options{DisabledTools: []string{"*_delete"}}, func(name string) bool {
+			return !strings.HasSuffix(name, "_delete")
+		}},
+		{"enabled then disabled", Options{EnabledTools: []string{"clockify_project_*"}, DisabledTools: []string{"clockify_project_delete"}}, func(name string) bool {
+			return strings.HasPrefix(name, "clockify_project_") && name != "clockify_project_delete"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := registeredTools(t, tt.opts)
+			for name := range all {
+				if _, ok := got[name]; ok != tt.want(name) {
+					t.Errorf("%s registered = %v, want %v", name, ok, !ok)
+				}
+			}
+		})
+	}
+
+	if _, ok := registeredTools(t, Options{ReadOnly: true})["clockify_cache_refresh"]; !ok {
+		t.Error("read-only mode should keep clockify_cache_refresh, which does not touch Clockify")
+	}
+}
+
+func TestCheckToolPatterns(t *testing.T) {
+	if err := CheckToolPatterns([]string{"clockify_*", "clockify_tag_list"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckToolPatterns([]string{"clockify_[tag"}); err == nil {
+		t.Fatal("expected an error for a malformed pattern")
+	}
+}
+
+// newTestRegistry returns a registry whose client talks to a fake Clockify
+// server backed by handler, with retries and rate limiting disabled.
+func newTestRegistry(t *testing.T, handler http.Handler) *registry {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	client := clockify.NewClient("test-key",
+		clockify.WithBaseURL(srv.URL),
+		clockify.WithReportsURL(srv.URL+"/reports"),
+		clockify.WithRetryPolicy(clockify.RetryPolicy{MaxAttempts: 1}),
+		clockify.WithRateLimit(clockify.RateLimit{}),
+		clockify.WithReportsRateLimit(clockify.RateLimit{}),
+	)
+	return &registry{base: &session{client: client, defaultWorkspaceID: "ws1", cache: newTTLCache(0)}}
+}
+
+// callTool invokes a tool handler with the given arguments.
+func callTool(t *testing.T, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return result
+}
+
+// resultText returns the text of the first content item of a tool result.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) == 0 {
+		t.Fatal("tool result has no content")
+	}
+	tc, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected TextContent, got %T", result.Content[0])
+	}
+	return tc.Text
+}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,25 +9,37 @@ import (
 )
 
 func registerReportTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_report_summary",
 			mcp.WithDescription("Generate a summary report for a workspace. Use group_by to control grouping (e.g. USER for per-person totals, PROJECT for per-project totals)."),
-			mcp.WithString("start", mcp.Required(), mcp.Description("Report start date (ISO 8601, e.g. 2024-01-01T00:00:00Z)")),
-			mcp.WithString("end", mcp.Required(), mcp.Description("Report end date (ISO 8601)")),
+			readOnlyTool("Summary report"),
+			mcp.WithOutputSchema[clockify.SummaryReport](),
+			mcp.WithString("range", mcp.Description("Report period, e.g. 'last week', 'this month', 'last 30 days' (alternative to start and end)")),
+			mcp.WithString("start", mcp.Description("Report start date (ISO 8601 or natural, e.g. 'last monday'); overrides the start of range")),
+			mcp.WithString("end", mcp.Description("Report end date (ISO 8601 or natural, e.g. 'yesterday' for the end of that day); overrides the end of range")),
 			mcp.WithString("group_by", mcp.Description("Group results by: USER, PROJECT, CLIENT, TAG, TIMEENTRY (default: PROJECT)")),
 			mcp.WithString("project_id", mcp.Description("Filter by project ID")),
+			mcp.WithString("project", mcp.Description("Filter by project name (alternative to project_id)")),
+			mcp.WithString("client_id", mcp.Description("Filter by client ID")),
+			mcp.WithString("client", mcp.Description("Filter by client name (alternative to client_id)")),
 			mcp.WithString("user_id", mcp.Description("Filter by user ID")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		reportSummaryHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_report_detailed",
 			mcp.WithDescription("Generate a detailed report for a workspace"),
-			mcp.WithString("start", mcp.Required(), mcp.Description("Report start date (ISO 8601, e.g. 2024-01-01T00:00:00Z)")),
-			mcp.WithString("end", mcp.Required(), mcp.Description("Report end date (ISO 8601)")),
+			readOnlyTool("Detailed report"),
+			mcp.WithOutputSchema[clockify.DetailedReport](),
+			mcp.WithString("range", mcp.Description("Report period, e.g. 'last week', 'this month', 'last 30 days' (alternative to start and end)")),
+			mcp.WithString("start", mcp.Description("Report start date (ISO 8601 or natural, e.g. 'last monday'); overrides the start of range")),
+			mcp.WithString("end", mcp.Description("Report end date (ISO 8601 or natural, e.g. 'yesterday' for the end of that day); overrides the end of range")),
 			mcp.WithString("project_id", mcp.Description("Filter by project ID")),
+			mcp.WithString("project", mcp.Description("Filter by project name (alternative to project_id)")),
+			mcp.WithString("client_id", mcp.Description("Filter by client ID")),
+			mcp.WithString("client", mcp.Description("Filter by client name (alternative to client_id)")),
 			mcp.WithString("user_id", mcp.Description("Filter by user ID")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Page size (default 50)")),
@@ -40,37 +51,52 @@ func registerReportTools(s *server.MCPServer, r *registry) {
 
 func reportSummaryHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		start, err := req.RequireString("start")
+		p, err := r.parser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError("start is required"), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
-		end, err := req.RequireString("end")
+		start, end, err := filterRange(p, req, true)
 		if err != nil {
-			return mcp.NewToolResultError("end is required"), nil
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if start == "" || end == "" {
+			return mcp.NewToolResultError("range, or start and end, is required"), nil
 		}
 
 		groupBy := req.GetString("group_by", "PROJECT")
 		reportReq := clockify.SummaryReportRequest{
 			DateRangeStart: start,
 			DateRangeEnd:   end,
+			TimeZone:       p.Location.String(),
 			SummaryFilter:  &clockify.SummaryFilter{Groups: []string{groupBy}},
 		}
 
-		if projectID := req.GetString("project_id", ""); projectID != "" {
+		projectID, err := r.projectArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID != "" {
 			reportReq.Projects = &clockify.ReportProjectFilter{IDs: []string{projectID}}
 		}
+		clientID, err := r.clientArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+		if clientID != "" {
+			reportReq.Clients = &clockify.ReportClientFilter{IDs: []string{clientID}}
+		}
 		if userID := req.GetString("user_id", ""); userID != "" {
 			reportReq.Users = &clockify.ReportUsersFilter{IDs: []string{userID}}
 		}
 
-		report, err := r.client.GetSummaryReport(wsID, reportReq)
+		report, err := r.client(ctx).GetSummaryReport(ctx, wsID, reportReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get summary report: %v", err)), nil
+			return apiErrorResult("get summary report", err, "", wsID), nil
 		}
 
 		return resultJSON(report)
@@ -79,18 +105,21 @@ func reportSummaryHandler(r *registry) server.ToolHandlerFunc {
 
 func reportDetailedHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		start, err := req.RequireString("start")
+		p, err := r.parser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError("start is required"), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
-		end, err := req.RequireString("end")
+		start, end, err := filterRange(p, req, true)
 		if err != nil {
-			return mcp.NewToolResultError("end is required"), nil
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if start == "" || end == "" {
+			return mcp.NewToolResultError("range, or start and end, is required"), nil
 		}
 
 		page := req.GetInt("page", 1)
@@ -99,21 +128,33 @@ func reportDetailedHandler(r *registry) server.ToolHandlerFunc {
 		reportReq := clockify.DetailedReportRequest{
 			DateRangeStart: start,
 			DateRangeEnd:   end,
+			TimeZone:       p.Location.String(),
 			Page:           page,
 			PageSize:       pageSize,
 			DetailedFilter: &clockify.DetailedFilter{Page: page, PageSize: pageSize},
 		}
 
-		if projectID := req.GetString("project_id", ""); projectID != "" {
+		projectID, err := r.projectArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID != "" {
 			reportReq.Projects = &clockify.ReportProjectFilter{IDs: []string{projectID}}
 		}
+		clientID, err := r.clientArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+		if clientID != "" {
+			reportReq.Clients = &clockify.ReportClientFilter{IDs: []string{clientID}}
+		}
 		if userID := req.GetString("user_id", ""); userID != "" {
 			reportReq.Users = &clockify.ReportUsersFilter{IDs: []string{userID}}
 		}
 
-		report, err := r.client.GetDetailedReport(wsID, reportReq)
+		report, err := r.client(ctx).GetDetailedReport(ctx, wsID, reportReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get detailed report: %v", err)), nil
+			return apiErrorResult("get detailed report", err, "", wsID), nil
 		}
 
 		return resultJSON(report)
@@ -0,0 +1,294 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// candidate is an entity a name may resolve to.
+type candidate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// resolveError reports a name that matched no entity or several equally well.
+// It is returned to the agent as structured JSON so it can pick a candidate.
+type resolveError struct {
+	Kind       string      `json:"kind"`
+	Query      string      `json:"query"`
+	Reason     string      `json:"error"`
+	Candidates []candidate `json:"candidates,omitempty"`
+}
+
+func (e *resolveError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Kind, e.Query, e.Reason)
+}
+
+// Match quality tiers, best first. Only the best tier with any match counts,
+// so an exact match wins over names that merely contain the query.
+const (
+	matchNone = iota
+	matchFuzzy
+	matchPartial
+	matchExact
+)
+
+// matchScore rates how well name matches query, ignoring case and spacing.
+func matchScore(query, name string) int {
+	q, n := normalizeName(query), normalizeName(name)
+	if q == "" {
+		return matchNone
+	}
+	if q == n {
+		return matchExact
+	}
+	if strings.Contains(n, q) || tokensPrefixMatch(q, n) {
+		return matchPartial
+	}
+	if limit := utf8.RuneCountInString(q) / 4; limit > 0 && levenshtein(q, n) <= limit {
+		return matchFuzzy
+	}
+	return matchNone
+}
+
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// tokensPrefixMatch reports whether every word of query starts some word of
+// name, e.g. "web redes" matches "website redesign 2024".
+func tokensPrefixMatch(query, name string) bool {
+	words := strings.Fields(name)
+	for _, qw := range strings.Fields(query) {
+		found := false
+		for _, w := range words {
+			if strings.HasPrefix(w, qw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// resolveName picks the single candidate that best matches query.
+func resolveName(kind, query string, cands []candidate) (candidate, error) {
+	best := matchNone
+	var matches []candidate
+	for _, c := range cands {
+		score := matchScore(query, c.Name)
+		switch {
+		case score == matchNone || score < best:
+		case score > best:
+			best = score
+			matches = []candidate{c}
+		default:
+			matches = append(matches, c)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return candidate{}, &resolveError{Kind: kind, Query: query, Reason: "not found"}
+	case 1:
+		return matches[0], nil
+	default:
+		return candidate{}, &resolveError{Kind: kind, Query: query, Reason: "ambiguous", Candidates: matches}
+	}
+}
+
+// resolveErrorResult converts a resolution failure into a tool error. Name
+// lookups become structured JSON; anything else is an API failure.
+func resolveErrorResult(err error, wsID string) *mcp.CallToolResult {
+	var re *resolveError
+	if errors.As(err, &re) {
+		b, _ := json.Marshal(re)
+		return mcp.NewToolResultError(string(b))
+	}
+	return apiErrorResult("resolve names", err, "", wsID)
+}
+
+// projectArg returns the project ID from project_id, or resolves the project
+// argument by name. Both empty yields "".
+func (r *registry) projectArg(ctx context.Context, req mcp.CallToolRequest, wsID string) (string, error) {
+	if id := req.GetString("project_id", ""); id != "" {
+		return id, nil
+	}
+	name := req.GetString("project", "")
+	if name == "" {
+		return "", nil
+	}
+	projects, err := r.allProjects(ctx, wsID, false)
+	if err != nil {
+		return "", err
+	}
+	cands := make([]candidate, len(projects.items))
+	for i, p := range projects.items {
+		cands[i] = candidate{ID: p.ID, Name: p.Name}
+	}
+	match, err := resolveName("project", name, cands)
+	return match.ID, err
+}
+
+// taskArg returns the task ID from task_id, or resolves the task argument by
+// name within projectID.
+func (r *registry) taskArg(ctx context.Context, req mcp.CallToolRequest, wsID, projectID string) (string, error) {
+	if id := req.GetString("task_id", ""); id != "" {
+		return id, nil
+	}
+	name := req.GetString("task", "")
+	if name == "" {
+		return "", nil
+	}
+	if projectID == "" {
+		return "", &resolveError{Kind: "task", Query: name, Reason: "a project is required to look up a task by name"}
+	}
+	tasks, err := r.allTasks(ctx, wsID, projectID)
+	if err != nil {
+		return "", err
+	}
+	cands := make([]candidate, len(tasks.items))
+	for i, t := range tasks.items {
+		cands[i] = candidate{ID: t.ID, Name: t.Name}
+	}
+	match, err := resolveName("task", name, cands)
+	return match.ID, err
+}
+
+// clientArg returns the client ID from client_id, or resolves the client
+// argument by name.
+func (r *registry) clientArg(ctx context.Context, req mcp.CallToolRequest, wsID string) (string, error) {
+	if id := req.GetString("client_id", ""); id != "" {
+		return id, nil
+	}
+	name := req.GetString("client", "")
+	if name == "" {
+		return "", nil
+	}
+	clients, err := r.allClients(ctx, wsID)
+	if err != nil {
+		return "", err
+	}
+	cands := make([]candidate, len(clients.items))
+	for i, c := range clients.items {
+		cands[i] = candidate{ID: c.ID, Name: c.Name}
+	}
+	match, err := resolveName("client", name, cands)
+	return match.ID, err
+}
+
+// tagsArg combines tag_ids with the tags resolved by name. With
+// create_missing set, tag names that match nothing are created once every
+// name has resolved; in a dry run they are returned as newTags instead.
+func (r *registry) tagsArg(ctx context.Context, req mcp.CallToolRequest, wsID string) (ids, newTags []string, err error) {
+	ids = req.GetStringSlice("tag_ids", nil)
+	names := req.GetStringSlice("tags", nil)
+	if len(names) == 0 {
+		return ids, nil, nil
+	}
+
+	tags, err := r.allTags(ctx, wsID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cands := make([]candidate, len(tags.items))
+	for i, t := range tags.items {
+		cands[i] = candidate{ID: t.ID, Name: t.Name}
+	}
+
+	// Resolve every name before creating any tag, so a name that fails to
+	// resolve leaves no new tags behind.
+	createMissing := req.GetBool("create_missing", false)
+	var missing []string
+	for _, name := range names {
+		match, err := resolveName("tag", name, cands)
+		var re *resolveError
+		if createMissing && errors.As(err, &re) && re.Reason == "not found" {
+			name = strings.TrimSpace(name)
+			if !slices.ContainsFunc(missing, func(m string) bool { return normalizeName(m) == normalizeName(name) }) {
+				missing = append(missing, name)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		// A partial or fuzzy match could be the wrong tag, and the one asked
+		// for would never be created, so creating needs the exact name.
+		if createMissing && normalizeName(match.Name) != normalizeName(name) {
+			return nil, nil, &resolveError{Kind: "tag", Query: name, Reason: "no exact match", Candidates: []candidate{match}}
+		}
+		ids = append(ids, match.ID)
+	}
+
+	if r.isDryRun(req) {
+		return ids, missing, nil
+	}
+	for _, name := range missing {
+		tag, err := r.client(ctx).CreateTag(ctx, wsID, clockify.CreateTagRequest{Name: name})
+		if err != nil {
+			return nil, nil, err
+		}
+		r.cache(ctx).invalidate(tagsKey(wsID))
+		ids = append(ids, tag.ID)
+	}
+	return ids, nil, nil
+}
+
+// entryRefs are the project, task and tag IDs of a time entry, plus the
+// tags a dry run would have created.
+type entryRefs struct {
+	projectID string
+	taskID    string
+	tagIDs    []string
+	newTags   []string
+}
+
+// entryRefsArg resolves the project, task and tag arguments shared by the
+// timer and time entry tools.
+func (r *registry) entryRefsArg(ctx context.Context, req mcp.CallToolRequest, wsID string) (entryRefs, error) {
+	var refs entryRefs
+	var err error
+	if refs.projectID, err = r.projectArg(ctx, req, wsID); err != nil {
+		return refs, err
+	}
+	if refs.taskID, err = r.taskArg(ctx, req, wsID, refs.projectID); err != nil {
+		return refs, err
+	}
+	if refs.tagIDs, refs.newTags, err = r.tagsArg(ctx, req, wsID); err != nil {
+		return refs, err
+	}
+	return refs, nil
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResolveName(t *testing.T) {
+	cands := []candidate{
+		{ID: "1", Name: "Website Redesign"},
+		{ID: "2", Name: "Website Maintenance"},
+		{ID: "3", Name: "Internal"},
+		{ID: "4", Name: "Mobile App"},
+		{ID: "5", Name: "Mobile App v2"},
+	}
+
+	tests := []struct {
+		query  string
+		wantID string
+		reason string
+	}{
+		{"internal", "3", ""},
+		{"  WEBSITE   redesign ", "1", ""},
+		{"web redes", "1", ""},
+		{"maint", "2", ""},
+		{"Intrenal", "3", ""},
+		{"mobile app", "4", ""},
+		{"website", "", "ambiguous"},
+		{"billing", "", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, err := resolveName("project", tt.query, cands)
+			if tt.reason == "" {
+				if err != nil || got.ID != tt.wantID {
+					t.Fatalf("got %+v, %v; want ID %s", got, err, tt.wantID)
+				}
+				return
+			}
+			re, ok := err.(*resolveError)
+			if !ok || re.Reason != tt.reason {
+				t.Fatalf("expected %q error, got %v", tt.reason, err)
+			}
+		})
+	}
+}
+
+func TestResolveName_AmbiguousListsCandidates(t *testing.T) {
+	_, err := resolveName("project", "web", []candidate{
+		{ID: "1", Name: "Website Redesign"},
+		{ID: "2", Name: "Website Maintenance"},
+		{ID: "3", Name: "Internal"},
+	})
+
+	result := resolveErrorResult(err, "ws1")
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	var payload resolveError
+	if err := json.Unmarshal([]byte(resultText(t, result)), &payload); err != nil {
+		t.Fatalf("error is not structured JSON: %v", err)
+	}
+	if payload.Kind != "project" || payload.Reason != "ambiguous" || len(payload.Candidates) != 2 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+// fakeWorkspace serves projects, tasks and tags for name resolution tests and
+// records the bodies of time entry and tag creation requests.
+type fakeWorkspace struct {
+	entryBodies []string
+	createdTags []string
+}
+
+func (f *fakeWorkspace) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		w.Write([]byte(`{"id":"u1"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/projects":
+		w.Write([]byte(`[{"id":"p1","name":"Website Redesign"},{"id":"p2","name":"Internal"}]`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/projects/p1/tasks":
+		w.Write([]byte(`[{"id":"k1","name":"Design"},{"id":"k2","name":"Development"}]`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/tags":
+		w.Write([]byte(`[{"id":"g1","name":"Meeting"}]`))
+	case r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws1/tags":
+		b, _ := io.ReadAll(r.Body)
+		f.createdTags = append(f.createdTags, string(b))
+		w.Write([]byte(`{"id":"g2","name":"Urgent"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws1/time-entries":
+		b, _ := io.ReadAll(r.Body)
+		f.entryBodies = append(f.entryBodies, string(b))
+		w.Write([]byte(`{"id":"e1"}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestTimerStart_ResolvesNames(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerStartHandler(r), map[string]any{
+		"project":        "website",
+		"task":           "design",
+		"tags":           []any{"meeting", "Urgent"},
+		"create_missing": true,
+	})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+
+	if len(fake.createdTags) != 1 || !strings.Contains(fake.createdTags[0], `"Urgent"`) {
+		t.Fatalf("expected Urgent tag to be created, got %v", fake.createdTags)
+	}
+	if len(fake.entryBodies) != 1 {
+		t.Fatalf("expected one timer start request, got %d", len(fake.entryBodies))
+	}
+	var body struct {
+		ProjectID string   `json:"projectId"`
+		TaskID    string   `json:"taskId"`
+		TagIDs    []string `json:"tagIds"`
+	}
+	json.Unmarshal([]byte(fake.entryBodies[0]), &body)
+	if body.ProjectID != "p1" || body.TaskID != "k1" || strings.Join(body.TagIDs, ",") != "g1,g2" {
+		t.Fatalf("unexpected request body: %s", fake.entryBodies[0])
+	}
+}
+
+func TestTimerStart_UnknownTagWithoutCreateMissingFails(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerStartHandler(r), map[string]any{"tags": []any{"Urgent"}})
+	if !result.IsError || !strings.Contains(resultText(t, result), `"not found"`) {
+		t.Fatalf("expected not found error, got %s", resultText(t, result))
+	}
+	if len(fake.createdTags) != 0 || len(fake.entryBodies) != 0 {
+		t.Fatal("expected no writes after a failed lookup")
+	}
+}
+
+func TestTimerStart_CreateMissingNeedsExactTagName(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+
+	// "meet" only partly matches the existing Meeting tag.
+	result := callTool(t, timerStartHandler(r), map[string]any{"tags": []any{"meet"}, "create_missing": true})
+	if !result.IsError {
+		t.Fatalf("expected an error, got %s", resultText(t, result))
+	}
+	var re resolveError
+	json.Unmarshal([]byte(resultText(t, result)), &re)
+	if re.Reason != "no exact match" || len(re.Candidates) != 1 || re.Candidates[0].Name != "Meeting" {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if len(fake.createdTags) != 0 || len(fake.entryBodies) != 0 {
+		t.Fatal("expected no writes after a failed lookup")
+	}
+}
+
+func TestTimerStart_CreateMissingResolvesEveryTagFirst(t *testing.T) {
+	fake := &fakeWorkspace{}
+	r := newTestRegistry(t, fake)
+
+	// Urgent would be created, but "meet" fails to resolve afterwards.
+	result := callTool(t, timerStartHandler(r), map[string]any{"tags": []any{"Urgent", "meet"}, "create_missing": true})
+	if !result.IsError || !strings.Contains(resultText(t, result), "no exact match") {
+		t.Fatalf("expected a no exact match error, got %s", resultText(t, result))
+	}
+	if len(fake.createdTags) != 0 || len(fake.entryBodies) != 0 {
+		t.Fatalf("expected no writes after a failed lookup, got tags %v", fake.createdTags)
+	}
+}
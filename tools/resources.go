@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource URIs. Workspace-scoped resources are templates over the workspace
+// ID.
+const (
+	workspacesURI    = "clockify://workspaces"
+	currentTimerURI  = "clockify://timer/current"
+	projectsTemplate = "clockify://workspace/{id}/projects"
+	tagsTemplate     = "clockify://workspace/{id}/tags"
+	clientsTemplate  = "clockify://workspace/{id}/clients"
+	resourceMIMEType = "application/json"
+)
+
+func registerResources(s *server.MCPServer, r *registry) {
+	r.addResource(s,
+		mcp.NewResource(workspacesURI, "Workspaces",
+			mcp.WithResourceDescription("Workspaces available to the current user"),
+			mcp.WithMIMEType(resourceMIMEType),
+		),
+		workspacesResourceHandler(r),
+	)
+
+	r.addResource(s,
+		mcp.NewResource(currentTimerURI, "Running timer",
+			mcp.WithResourceDescription("The current user's running timer in the default workspace, if any"),
+			mcp.WithMIMEType(resourceMIMEType),
+		),
+		currentTimerResourceHandler(r),
+	)
+
+	r.addResourceTemplate(s,
+		mcp.NewResourceTemplate(projectsTemplate, "Projects",
+			mcp.WithTemplateDescription("Active projects in a workspace"),
+			mcp.WithTemplateMIMEType(resourceMIMEType),
+		),
+		projectsResourceHandler(r),
+	)
+
+	r.addResourceTemplate(s,
+		mcp.NewResourceTemplate(tagsTemplate, "Tags",
+			mcp.WithTemplateDescription("Tags in a workspace"),
+			mcp.WithTemplateMIMEType(resourceMIMEType),
+		),
+		tagsResourceHandler(r),
+	)
+
+	r.addResourceTemplate(s,
+		mcp.NewResourceTemplate(clientsTemplate, "Clients",
+			mcp.WithTemplateDescription("Clients in a workspace"),
+			mcp.WithTemplateMIMEType(resourceMIMEType),
+		),
+		clientsResourceHandler(r),
+	)
+}
+
+// addResource registers a resource whose handler runs in the caller's session.
+func (r *registry) addResource(s *server.MCPServer, resource mcp.Resource, handler server.ResourceHandlerFunc) {
+	s.AddResource(resource, r.withResourceSession(handler))
+}
+
+// addResourceTemplate registers a resource template whose handler runs in the
+// caller's session.
+func (r *registry) addResourceTemplate(s *server.MCPServer, template mcp.ResourceTemplate, handler server.ResourceHandlerFunc) {
+	s.AddResourceTemplate(template, server.ResourceTemplateHandlerFunc(r.withResourceSession(handler)))
+}
+
+// withResourceSession runs next in the caller's session.
+func (r *registry) withResourceSession(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		ctx, err := r.sessionContext(ctx)
+		if err != nil {
+			return nil, errors.New(describeSessionError(err))
+		}
+		return next(ctx, req)
+	}
+}
+
+// resourceJSON returns data as the JSON contents of the resource at uri.
+func resourceJSON(uri string, data any) ([]mcp.ResourceContents, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal JSON resource: %w", err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{URI: uri, MIMEType: resourceMIMEType, Text: string(b)},
+	}, nil
+}
+
+// resourceError describes a failed Clockify call made to read uri.
+func resourceError(uri string, err error, subject, wsID string) error {
+	return fmt.Errorf("failed to read %s: %s", uri, describeAPIError(err, subject, wsID))
+}
+
+// templateArg returns a variable matched from a resource template URI.
+func templateArg(req mcp.ReadResourceRequest, name string) string {
+	switch v := req.Params.Arguments[name].(type) {
+	case string:
+		return v
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+func workspacesResourceHandler(r *registry) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		workspaces, err := r.workspaces(ctx)
+		if err != nil {
+			return nil, resourceError(req.Params.URI, err, "", "")
+		}
+		return resourceJSON(req.Params.URI, map[string]any{"workspaces": workspaces})
+	}
+}
+
+func currentTimerResourceHandler(r *registry) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		state, err := r.timerState(ctx)
+		if err != nil {
+			return nil, resourceError(req.Params.URI, err, "", r.workspaceID(ctx, ""))
+		}
+		return resourceJSON(req.Params.URI, state)
+	}
+}
+
+// timerState describes the caller's running timer in their default
+// workspace. It backs the timer resource and its subscriptions.
+func (r *registry) timerState(ctx context.Context) (map[string]any, error) {
+	wsID := r.workspaceID(ctx, "")
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.client(ctx).GetRunningTimer(ctx, wsID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	state := map[string]any{"workspace_id": wsID, "running": entry != nil}
+	if entry != nil {
+		loc, _ := r.userLocation(ctx)
+		state["entry"] = localEntry(*entry, loc)
+	}
+	return state, nil
+}
+
+func projectsResourceHandler(r *registry) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		wsID := templateArg(req, "id")
+		projects, err := r.allProjects(ctx, wsID, false)
+		if err != nil {
+			return nil, resourceError(req.Params.URI, err, "", wsID)
+		}
+		return resourceJSON(req.Params.URI, map[string]any{"projects": projects.items, "truncated": projects.truncated})
+	}
+}
+
+func tagsResourceHandler(r *registry) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		wsID := templateArg(req, "id")
+		tags, err := r.allTags(ctx, wsID)
+		if err != nil {
+			return nil, resourceError(req.Params.URI, err, "", wsID)
+		}
+		return resourceJSON(req.Params.URI, map[string]any{"tags": tags.items, "truncated": tags.truncated})
+	}
+}
+
+func clientsResourceHandler(r *registry) server.ResourceHandlerFunc {
+	return func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		wsID := templateArg(req, "id")
+		clients, err := r.allClients(ctx, wsID)
+		if err != nil {
+			return nil, resourceError(req.Params.URI, err, "", wsID)
+		}
+		return resourceJSON(req.Params.URI, map[string]any{"clients": clients.items, "truncated": clients.truncated})
+	}
+}
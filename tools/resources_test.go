@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// readResource sends a resources/read request through s and returns the text
+// of the first content item, or the JSON-RPC error message.
+func readResource(t *testing.T, s *server.MCPServer, uri string) (text string, errMsg string) {
+	t.Helper()
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": "resources/read",
+		"params": map[string]any{"uri": uri},
+	})
+	resp := s.HandleMessage(context.Background(), msg)
+
+	switch resp := resp.(type) {
+	case mcp.JSONRPCResponse:
+		result, ok := resp.Result.(mcp.ReadResourceResult)
+		if !ok || len(result.Contents) == 0 {
+			t.Fatalf("unexpected result: %#v", resp.Result)
+		}
+		return result.Contents[0].(mcp.TextResourceContents).Text, ""
+	case mcp.JSONRPCError:
+		return "", resp.Error.Message
+	default:
+		t.Fatalf("unexpected response: %#v", resp)
+		return "", ""
+	}
+}
+
+func TestResources_Read(t *testing.T) {
+	r := newTestRegistry(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id":"u1"}`))
+		case "/workspaces":
+			w.Write([]byte(`[{"id":"ws1","name":"Acme"}]`))
+		case "/workspaces/ws1/projects":
+			w.Write([]byte(`[{"id":"p1","name":"Website"}]`))
+		case "/workspaces/ws1/tags":
+			w.Write([]byte(`[{"id":"g1","name":"Meeting"}]`))
+		case "/workspaces/ws1/clients":
+			w.Write([]byte(`[{"id":"c1","name":"Globex"}]`))
+		case "/workspaces/ws1/user/u1/time-entries":
+			w.Write([]byte(`[{"id":"e1","description":"Coding","timeInterval":{"start":"2024-03-13T09:00:00Z"}}]`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	s := server.NewMCPServer("test", "1.0")
+	registerResources(s, r)
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"clockify://workspaces", `"name":"Acme"`},
+		{"clockify://workspace/ws1/projects", `"name":"Website"`},
+		{"clockify://workspace/ws1/tags", `"name":"Meeting"`},
+		{"clockify://workspace/ws1/clients", `"name":"Globex"`},
+		{"clockify://timer/current", `"running":true`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			text, errMsg := readResource(t, s, tt.uri)
+			if errMsg != "" || !strings.Contains(text, tt.want) {
+				t.Fatalf("got %q (error %q), want it to contain %s", text, errMsg, tt.want)
+			}
+		})
+	}
+}
+
+func TestResources_ReportsAPIErrors(t *testing.T) {
+	r := newTestRegistry(t, http.NotFoundHandler())
+	s := server.NewMCPServer("test", "1.0")
+	registerResources(s, r)
+
+	_, errMsg := readResource(t, s, "clockify://workspace/nope/tags")
+	if !strings.Contains(errMsg, "workspace nope") {
+		t.Fatalf("expected a workspace not found error, got %q", errMsg)
+	}
+}
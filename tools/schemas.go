@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// Annotation presets. They tell clients which tools are safe to run without
+// asking: read-only tools never change Clockify data, create tools only add
+// to it, and update and delete tools overwrite or remove what is there.
+// Replace tools change what is there and add to it, as switching timers
+// does, so repeating a call changes more. Stop tools finish what is running
+// without removing anything, so repeating one is harmless. Every Clockify
+// tool reaches an external service, so all are open-world; local tools only
+// touch this server's own state, such as its cache or audit log.
+
+func readOnlyTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, true, false, true, true)
+}
+
+func createTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, false, false, true)
+}
+
+func updateTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, true, true, true)
+}
+
+func replaceTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, true, false, true)
+}
+
+func stopTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, false, true, true)
+}
+
+func deleteTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, true, true, true)
+}
+
+func localReadOnlyTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, true, false, true, false)
+}
+
+func localTool(title string) mcp.ToolOption {
+	return toolAnnotation(title, false, false, true, false)
+}
+
+func toolAnnotation(title string, readOnly, destructive, idempotent, openWorld bool) mcp.ToolOption {
+	return mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(readOnly),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(openWorld),
+	})
+}
+
+// Tool outputs that wrap Clockify objects. Handlers build these rather than
+// ad hoc maps, so the output schemas generated from them match what
+// resultJSON returns. Truncated is set when an all-pages listing hit its cap.
+
+type timeEntryListOutput struct {
+	Entries   []clockify.TimeEntry `json:"entries"`
+	Truncated bool                 `json:"truncated,omitempty"`
+}
+
+type timeEntryUpdateOutput struct {
+	Entry   clockify.TimeEntry     `json:"entry"`
+	Changes map[string]fieldChange `json:"changes"`
+}
+
+type timerSwitchOutput struct {
+	Stopped *clockify.TimeEntry `json:"stopped,omitempty"`
+	Started clockify.TimeEntry  `json:"started"`
+}
+
+// timerResumeOutput is the started entry, or with Extended the previous
+// entry running again.
+type timerResumeOutput struct {
+	Entry       clockify.TimeEntry `json:"entry"`
+	ResumedFrom string             `json:"resumed_from"`
+	Extended    bool               `json:"extended,omitempty"`
+}
+
+type projectListOutput struct {
+	Projects  []clockify.Project `json:"projects"`
+	Truncated bool               `json:"truncated,omitempty"`
+}
+
+type taskListOutput struct {
+	Tasks     []clockify.Task `json:"tasks"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+type tagListOutput struct {
+	Tags      []clockify.Tag `json:"tags"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+type clientListOutput struct {
+	Clients   []clockify.ClockifyClient `json:"clients"`
+	Truncated bool                      `json:"truncated,omitempty"`
+}
+
+type userListOutput struct {
+	Users     []clockify.User `json:"users"`
+	Truncated bool            `json:"truncated,omitempty"`
+}
+
+type workspaceListOutput struct {
+	Workspaces []clockify.Workspace `json:"workspaces"`
+}
+
+type auditQueryOutput struct {
+	Records []audit.Record `json:"records"`
+}
+
+type cacheRefreshOutput struct {
+	Cleared int `json:"cleared"`
+}
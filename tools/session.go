@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// sessionIdleTTL is how long a caller's session is kept after its last tool
+// call.
+const sessionIdleTTL = 30 * time.Minute
+
+// session is the Clockify identity a tool call acts as: a client for one API
+// key, that user's default workspace, and a metadata cache private to them.
+type session struct {
+	client             *clockify.Client
+	defaultWorkspaceID string
+	cache              *ttlCache
+	lastUsed           time.Time
+}
+
+type apiKeyContextKey struct{}
+type sessionContextKey struct{}
+
+// WithAPIKey returns a context carrying a caller's Clockify API key. Tool
+// calls made under it act as that key's user rather than the server's own
+// key. The HTTP transports use it for per-caller keys.
+func WithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+func apiKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return key
+}
+
+// sessionPool creates and reuses one session per caller API key. Keys are
+// held only inside their client; the pool indexes sessions by digest.
+type sessionPool struct {
+	mu        sync.Mutex
+	newClient func(apiKey string) *clockify.Client
+	cacheTTL  time.Duration
+	now       func() time.Time
+	sessions  map[[sha256.Size]byte]*session
+}
+
+func newSessionPool(newClient func(apiKey string) *clockify.Client, cacheTTL time.Duration) *sessionPool {
+	return &sessionPool{
+		newClient: newClient,
+		cacheTTL:  cacheTTL,
+		now:       time.Now,
+		sessions:  map[[sha256.Size]byte]*session{},
+	}
+}
+
+// get returns the session for apiKey, creating it on first use. Creating a
+// session resolves the user's default workspace, so an invalid key fails here
+// and is not remembered.
+func (p *sessionPool) get(ctx context.Context, apiKey string) (*session, error) {
+	digest := sha256.Sum256([]byte(apiKey))
+	now := p.now()
+
+	p.mu.Lock()
+	for d, s := range p.sessions {
+		if now.Sub(s.lastUsed) > sessionIdleTTL {
+			delete(p.sessions, d)
+		}
+	}
+	if s, ok := p.sessions[digest]; ok {
+		s.lastUsed = now
+		p.mu.Unlock()
+		return s, nil
+	}
+	p.mu.Unlock()
+
+	s := &session{client: p.newClient(apiKey), cache: newTTLCache(p.cacheTTL), lastUsed: now}
+	wsID, err := defaultWorkspace(ctx, s.client)
+	if err != nil {
+		return nil, err
+	}
+	s.defaultWorkspaceID = wsID
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing, ok := p.sessions[digest]; ok {
+		return existing, nil
+	}
+	p.sessions[digest] = s
+	return s, nil
+}
+
+// defaultWorkspace picks the user's active workspace, or their first one.
+func defaultWorkspace(ctx context.Context, client *clockify.Client) (string, error) {
+	user, err := client.GetCurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	if user.ActiveWorkspace != "" {
+		return user.ActiveWorkspace, nil
+	}
+	workspaces, err := client.GetWorkspaces(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(workspaces) == 0 {
+		return "", fmt.Errorf("no workspaces found for this API key")
+	}
+	return workspaces[0].ID, nil
+}
+
+// addTool registers a tool whose handler runs in the caller's session, unless
+// the tool filters leave it out. Tools that change Clockify data get a
+// dry_run argument and are audited when a journal is set, deletes that ask
+// for confirmation get a confirm_token argument, and tools with an output
+// schema also return StructuredContent when it is enabled. The output schema
+// of a tool with a dry_run argument also accepts its dry-run output.
+func (r *registry) addTool(s *server.MCPServer, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if !r.toolAllowed(tool) {
+		return
+	}
+	if changesClockify(tool) {
+		dryRunArg(&tool)
+		if tool.OutputSchema.Type != "" {
+			allowDryRunOutput(&tool)
+		}
+		if r.journal != nil && tool.Name != undoToolName {
+			handler = r.withAudit(tool.Name, handler)
+		}
+	}
+	if slices.Contains(r.confirmDeletes, tool.Name) {
+		confirmTokenArg(&tool)
+	}
+	handler = r.withSession(handler)
+	if hasOutputSchema(tool) && r.structured != StructuredOff {
+		handler = r.withStructuredContent(handler)
+	}
+	s.AddTool(tool, handler)
+}
+
+// errNoAPIKey refuses calls without a caller key when the server has none,
+// or when callers must bring their own.
+var errNoAPIKey = errors.New("a Clockify API key is required: send it in the X-Api-Key header or as a bearer token")
+
+// withSession runs next in the caller's session.
+func (r *registry) withSession(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, err := r.sessionContext(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(describeSessionError(err)), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+// sessionContext resolves the session for the caller's API key, if ctx
+// carries one, and returns ctx bound to it. Without per-caller keys the
+// server's own session is used. With them, a caller without a key gets
+// errNoAPIKey unless keyFallback lets them use the server's session.
+func (r *registry) sessionContext(ctx context.Context) (context.Context, error) {
+	key := apiKeyFromContext(ctx)
+	if r.pool == nil || (key == "" && r.keyFallback) {
+		if r.base == nil {
+			return nil, errNoAPIKey
+		}
+		return ctx, nil
+	}
+	if key == "" {
+		return nil, errNoAPIKey
+	}
+	s, err := r.pool.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, sessionContextKey{}, s), nil
+}
+
+// describeSessionError explains why a caller's session could not start.
+func describeSessionError(err error) string {
+	switch {
+	case errors.Is(err, errNoAPIKey):
+		return err.Error()
+	case clockify.IsUnauthorized(err):
+		return "Clockify rejected the API key sent with this request"
+	default:
+		return "Failed to start Clockify session: " + describeAPIError(err, "", "")
+	}
+}
+
+// session returns the session a call runs in.
+func (r *registry) session(ctx context.Context) *session {
+	if s, ok := ctx.Value(sessionContextKey{}).(*session); ok {
+		return s
+	}
+	return r.base
+}
+
+// client returns the Clockify client for the caller.
+func (r *registry) client(ctx context.Context) *clockify.Client {
+	return r.session(ctx).client
+}
+
+// cache returns the caller's metadata cache.
+func (r *registry) cache(ctx context.Context) *ttlCache {
+	return r.session(ctx).cache
+}
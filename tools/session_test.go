@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// tenantServer answers as a different user and workspace per API key.
+type tenantServer struct {
+	userCalls atomic.Int32
+}
+
+func (f *tenantServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("X-Api-Key")
+	switch {
+	case key != "alice-key" && key != "bob-key":
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Api key does not exist","code":4003}`))
+	case r.URL.Path == "/user":
+		f.userCalls.Add(1)
+		name := strings.TrimSuffix(key, "-key")
+		w.Write([]byte(`{"id":"` + name + `","name":"` + name + `","activeWorkspace":"ws-` + name + `"}`))
+	case r.URL.Path == "/workspaces/ws-alice/tags":
+		w.Write([]byte(`[{"id":"t1","name":"alice-tag"}]`))
+	case r.URL.Path == "/workspaces/ws-bob/tags":
+		w.Write([]byte(`[{"id":"t2","name":"bob-tag"}]`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTenantRegistry(t *testing.T) (*registry, *tenantServer) {
+	t.Helper()
+	fake := &tenantServer{}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	r := &registry{pool: newSessionPool(func(apiKey string) *clockify.Client {
+		return clockify.NewClient(apiKey,
+			clockify.WithBaseURL(srv.URL),
+			clockify.WithRetryPolicy(clockify.RetryPolicy{MaxAttempts: 1}),
+			clockify.WithRateLimit(clockify.RateLimit{}),
+		)
+	}, 0)}
+	return r, fake
+}
+
+func callAs(t *testing.T, r *registry, apiKey string, handler func(*registry) server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	ctx := context.Background()
+	if apiKey != "" {
+		ctx = WithAPIKey(ctx, apiKey)
+	}
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	result, err := r.withSession(handler(r))(ctx, req)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return result
+}
+
+func TestSessions_IsolateCallers(t *testing.T) {
+	r, fake := newTenantRegistry(t)
+	for _, tc := range []struct{ key, want, other string }{
+		{"alice-key", "alice-tag", "bob-tag"},
+		{"bob-key", "bob-tag", "alice-tag"},
+		{"alice-key", "alice-tag", "bob-tag"},
+	} {
+		text := resultText(t, callAs(t, r, tc.key, tagListHandler, map[string]any{}))
+		if !strings.Contains(text, tc.want) || strings.Contains(text, tc.other) {
+			t.Fatalf("%s got %s", tc.key, text)
+		}
+	}
+
+	// Each key resolved its user once; the second alice call reused her session.
+	if got := fake.userCalls.Load(); got != 2 {
+		t.Fatalf("expected 2 user lookups, got %d", got)
+	}
+}
+
+func TestSessions_RejectsMissingAndInvalidKeys(t *testing.T) {
+	r, _ := newTenantRegistry(t)
+	result := callAs(t, r, "", userCurrentHandler, nil)
+	if !result.IsError || !strings.Contains(resultText(t, result), "API key is required") {
+		t.Fatalf("expected missing key error, got %s", resultText(t, result))
+	}
+
+	result = callAs(t, r, "mallory-key", userCurrentHandler, nil)
+	if !result.IsError || !strings.Contains(resultText(t, result), "rejected the API key") {
+		t.Fatalf("expected rejected key error, got %s", resultText(t, result))
+	}
+	if strings.Contains(resultText(t, result), "mallory-key") {
+		t.Fatal("error result leaks the API key")
+	}
+	if len(r.pool.sessions) != 0 {
+		t.Fatal("invalid key was kept in the session pool")
+	}
+}
+
+func TestSessions_KeylessCallersNeedFallbackForServerKey(t *testing.T) {
+	r, _ := newTenantRegistry(t)
+	r.base = &session{client: r.pool.newClient("alice-key"), defaultWorkspaceID: "ws-alice", cache: newTTLCache(0)}
+
+	result := callAs(t, r, "", tagListHandler, map[string]any{})
+	if !result.IsError || !strings.Contains(resultText(t, result), "API key is required") {
+		t.Fatalf("keyless caller used the server's key: %s", resultText(t, result))
+	}
+
+	r.keyFallback = true
+	result = callAs(t, r, "", tagListHandler, map[string]any{})
+	if result.IsError || !strings.Contains(resultText(t, result), "alice-tag") {
+		t.Fatalf("expected the server's tags, got %s", resultText(t, result))
+	}
+}
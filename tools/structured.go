@@ -0,0 +1,93 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// StructuredMode controls whether JSON tool results also carry
+// StructuredContent matching the tool's output schema. The text content is
+// sent either way.
+type StructuredMode int
+
+const (
+	// StructuredOff sends text only. It is the default because some clients
+	// fail to validate structuredContent.
+	StructuredOff StructuredMode = iota
+	// StructuredOn adds StructuredContent for every client.
+	StructuredOn
+	// StructuredAuto adds StructuredContent unless the client's initialize
+	// info names one known to reject it.
+	StructuredAuto
+)
+
+// structuredContentRejecters are client names, as sent in clientInfo, that
+// fail on structuredContent.
+var structuredContentRejecters = []string{"claude-code"}
+
+// ParseStructuredMode parses a structured_content setting: off, on or auto.
+// Empty means off.
+func ParseStructuredMode(s string) (StructuredMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "off", "false":
+		return StructuredOff, nil
+	case "on", "true":
+		return StructuredOn, nil
+	case "auto":
+		return StructuredAuto, nil
+	default:
+		return StructuredOff, fmt.Errorf("unknown structured_content %q (use off, on or auto)", s)
+	}
+}
+
+// withStructuredContent copies the JSON object of a successful text result
+// into StructuredContent when the calling client should get it.
+func (r *registry) withStructuredContent(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || result.StructuredContent != nil || !r.wantsStructured(ctx) {
+			return result, err
+		}
+		if len(result.Content) != 1 {
+			return result, nil
+		}
+		// Plain-text results, such as "No timer is currently running.",
+		// are not objects and stay text only.
+		if tc, ok := result.Content[0].(mcp.TextContent); ok {
+			var obj map[string]any
+			if json.Unmarshal([]byte(tc.Text), &obj) == nil && obj != nil {
+				result.StructuredContent = obj
+			}
+		}
+		return result, nil
+	}
+}
+
+// hasOutputSchema reports whether tool declares the shape of its result.
+func hasOutputSchema(tool mcp.Tool) bool {
+	return tool.OutputSchema.Type != "" || tool.RawOutputSchema != nil
+}
+
+// wantsStructured reports whether the client behind ctx gets
+// StructuredContent.
+func (r *registry) wantsStructured(ctx context.Context) bool {
+	switch r.structured {
+	case StructuredOn:
+		return true
+	case StructuredAuto:
+		session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+		if !ok {
+			return false
+		}
+		name := strings.ToLower(session.GetClientInfo().Name)
+		return name != "" && !slices.Contains(structuredContentRejecters, name)
+	default:
+		return false
+	}
+}
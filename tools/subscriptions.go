@@ -0,0 +1,169 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// defaultTimerPollInterval is how often subscribed timers are checked when
+// Options.TimerPollInterval is zero.
+const defaultTimerPollInterval = 30 * time.Second
+
+// JSON-RPC methods handled by Subscriptions.
+const (
+	methodSubscribe   = "resources/subscribe"
+	methodUnsubscribe = "resources/unsubscribe"
+)
+
+// Subscriptions answers resources/subscribe and resources/unsubscribe and
+// sends notifications/resources/updated when a subscribed resource changes.
+// Only the running timer supports subscriptions; it is polled from Clockify.
+//
+// mcp-go does not route subscription requests to the server, so transports
+// offer each incoming message to HandleMessage before passing it on.
+type Subscriptions struct {
+	server   *server.MCPServer
+	r        *registry
+	interval time.Duration
+
+	mu   sync.Mutex
+	subs map[subscriptionKey]*subscription
+}
+
+type subscriptionKey struct {
+	sessionID string
+	uri       string
+}
+
+type subscription struct {
+	ctx  context.Context // the subscriber's API key, detached from its request
+	last []byte          // resource contents at the last check
+}
+
+func newSubscriptions(s *server.MCPServer, r *registry, interval time.Duration) *Subscriptions {
+	if interval <= 0 {
+		interval = defaultTimerPollInterval
+	}
+	return &Subscriptions{server: s, r: r, interval: interval, subs: map[subscriptionKey]*subscription{}}
+}
+
+// HandleMessage answers message if it is a subscription request from the
+// given MCP session, returning the response to send back. For any other
+// message ok is false and the message should go to the MCP server as usual.
+// ctx carries the caller's API key, as for tool calls.
+func (s *Subscriptions) HandleMessage(ctx context.Context, sessionID string, message []byte) (resp mcp.JSONRPCMessage, ok bool) {
+	var req struct {
+		ID     mcp.RequestId `json:"id"`
+		Method string        `json:"method"`
+		Params struct {
+			URI string `json:"uri"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(message, &req); err != nil {
+		return nil, false
+	}
+
+	key := subscriptionKey{sessionID: sessionID, uri: req.Params.URI}
+	switch req.Method {
+	case methodSubscribe:
+		if sessionID == "" {
+			return mcp.NewJSONRPCError(req.ID, mcp.INVALID_REQUEST, "subscriptions need an MCP session", nil), true
+		}
+		if key.uri != currentTimerURI {
+			return mcp.NewJSONRPCError(req.ID, mcp.INVALID_PARAMS,
+				fmt.Sprintf("%s does not support subscriptions; only %s does", key.uri, currentTimerURI), nil), true
+		}
+		ctx = context.WithoutCancel(ctx)
+		state, err := s.read(ctx)
+		if err != nil {
+			return mcp.NewJSONRPCError(req.ID, mcp.INTERNAL_ERROR, err.Error(), nil), true
+		}
+		s.mu.Lock()
+		s.subs[key] = &subscription{ctx: ctx, last: state}
+		s.mu.Unlock()
+	case methodUnsubscribe:
+		s.mu.Lock()
+		delete(s.subs, key)
+		s.mu.Unlock()
+	default:
+		return nil, false
+	}
+	return mcp.NewJSONRPCResultResponse(req.ID, mcp.EmptyResult{}), true
+}
+
+// Run checks subscribed resources every poll interval until ctx is done.
+func (s *Subscriptions) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.poll()
+		}
+	}
+}
+
+// poll re-reads every subscribed resource and notifies the subscribers whose
+// resource changed. Subscriptions of sessions that have gone away are dropped.
+func (s *Subscriptions) poll() {
+	s.mu.Lock()
+	subs := maps.Clone(s.subs)
+	s.mu.Unlock()
+
+	// Subscribers sharing an API key share one Clockify lookup per poll. A
+	// failed lookup is retried on the next poll.
+	states := map[string][]byte{}
+	for key, sub := range subs {
+		apiKey := apiKeyFromContext(sub.ctx)
+		state, seen := states[apiKey]
+		if !seen {
+			state, _ = s.read(sub.ctx)
+			states[apiKey] = state
+		}
+		if state == nil {
+			continue
+		}
+
+		s.mu.Lock()
+		changed := !bytes.Equal(sub.last, state)
+		sub.last = state
+		s.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		err := s.server.SendNotificationToSpecificClient(key.sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": key.uri})
+		if errors.Is(err, server.ErrSessionNotFound) {
+			s.mu.Lock()
+			if s.subs[key] == sub {
+				delete(s.subs, key)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// read returns the current contents of the timer resource for the caller in
+// ctx.
+func (s *Subscriptions) read(ctx context.Context) ([]byte, error) {
+	ctx, err := s.r.sessionContext(ctx)
+	if err != nil {
+		return nil, errors.New(describeSessionError(err))
+	}
+	state, err := s.r.timerState(ctx)
+	if err != nil {
+		return nil, resourceError(currentTimerURI, err, "", s.r.workspaceID(ctx, ""))
+	}
+	return json.Marshal(state)
+}
@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fakeClientSession is an initialized MCP session that buffers notifications.
+type fakeClientSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func newFakeClientSession(id string) *fakeClientSession {
+	return &fakeClientSession{id: id, notifications: make(chan mcp.JSONRPCNotification, 10)}
+}
+
+func (f *fakeClientSession) Initialize()       {}
+func (f *fakeClientSession) Initialized() bool { return true }
+func (f *fakeClientSession) SessionID() string { return f.id }
+func (f *fakeClientSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return f.notifications
+}
+
+func subscriptionMessage(method, uri string) []byte {
+	msg, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0", "id": 1, "method": method,
+		"params": map[string]any{"uri": uri},
+	})
+	return msg
+}
+
+func TestSubscriptions_NotifyWhenTimerChanges(t *testing.T) {
+	var running atomic.Value
+	running.Store(`[]`)
+	r := newTestRegistry(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id":"u1"}`))
+		case "/workspaces/ws1/user/u1/time-entries":
+			w.Write([]byte(running.Load().(string)))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	s := server.NewMCPServer("test", "1.0", server.WithResourceCapabilities(true, false))
+	subs := newSubscriptions(s, r, 0)
+	client := newFakeClientSession("s1")
+	if err := s.RegisterSession(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, ok := subs.HandleMessage(context.Background(), "s1", subscriptionMessage(methodSubscribe, currentTimerURI))
+	if _, isResult := resp.(mcp.JSONRPCResponse); !ok || !isResult {
+		t.Fatalf("subscribe returned %#v", resp)
+	}
+
+	expectNotification := func(want bool) {
+		t.Helper()
+		subs.poll()
+		select {
+		case n := <-client.notifications:
+			if !want {
+				t.Fatalf("unexpected notification %#v", n)
+			}
+			if n.Method != mcp.MethodNotificationResourceUpdated || n.Params.AdditionalFields["uri"] != currentTimerURI {
+				t.Fatalf("unexpected notification %#v", n)
+			}
+		default:
+			if want {
+				t.Fatal("expected a notification")
+			}
+		}
+	}
+
+	expectNotification(false)
+	running.Store(`[{"id":"e1","description":"Coding","timeInterval":{"start":"2024-03-13T09:00:00Z"}}]`)
+	expectNotification(true)
+	expectNotification(false)
+	running.Store(`[{"id":"e1","description":"Code review","timeInterval":{"start":"2024-03-13T09:00:00Z"}}]`)
+	expectNotification(true)
+	running.Store(`[]`)
+	expectNotification(true)
+
+	subs.HandleMessage(context.Background(), "s1", subscriptionMessage(methodUnsubscribe, currentTimerURI))
+	running.Store(`[{"id":"e2","timeInterval":{"start":"2024-03-13T11:00:00Z"}}]`)
+	expectNotification(false)
+}
+
+func TestSubscriptions_RejectsUnsupportedRequests(t *testing.T) {
+	r := newTestRegistry(t, http.NotFoundHandler())
+	subs := newSubscriptions(server.NewMCPServer("test", "1.0"), r, 0)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		uri       string
+		want      string
+	}{
+		{"no session", "", currentTimerURI, "need an MCP session"},
+		{"other resource", "s1", workspacesURI, "does not support subscriptions"},
+		{"Clockify error", "s1", currentTimerURI, "failed to read " + currentTimerURI},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, ok := subs.HandleMessage(context.Background(), tt.sessionID, subscriptionMessage(methodSubscribe, tt.uri))
+			rpcErr, isErr := resp.(mcp.JSONRPCError)
+			if !ok || !isErr || !strings.Contains(rpcErr.Error.Message, tt.want) {
+				t.Fatalf("got %#v, want an error containing %q", resp, tt.want)
+			}
+		})
+	}
+
+	if _, ok := subs.HandleMessage(context.Background(), "s1", []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)); ok {
+		t.Fatal("tools/list should pass through")
+	}
+	if len(subs.subs) != 0 {
+		t.Fatalf("failed subscriptions were kept: %v", subs.subs)
+	}
+}
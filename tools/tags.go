@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,9 +9,12 @@ import (
 )
 
 func registerTagTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_tag_list",
-			mcp.WithDescription("List tags in a workspace (paginated, default page 1, page_size 50)"),
+			mcp.WithDescription("List tags in a workspace (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List tags"),
+			mcp.WithOutputSchema[tagListOutput](),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of tags per page (default 50)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -20,18 +22,22 @@ func registerTagTools(s *server.MCPServer, r *registry) {
 		tagListHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_tag_create",
 			mcp.WithDescription("Create a new tag"),
+			createTool("Create tag"),
+			mcp.WithOutputSchema[clockify.Tag](),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Tag name")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		tagCreateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_tag_update",
 			mcp.WithDescription("Update a tag"),
+			updateTool("Update tag"),
+			mcp.WithOutputSchema[clockify.Tag](),
 			mcp.WithString("tag_id", mcp.Required(), mcp.Description("Tag ID to update")),
 			mcp.WithString("name", mcp.Description("New tag name")),
 			mcp.WithBoolean("archived", mcp.Description("Whether the tag is archived")),
@@ -40,9 +46,10 @@ func registerTagTools(s *server.MCPServer, r *registry) {
 		tagUpdateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_tag_delete",
 			mcp.WithDescription("Delete a tag"),
+			deleteTool("Delete tag"),
 			mcp.WithString("tag_id", mcp.Required(), mcp.Description("Tag ID to delete")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
@@ -52,26 +59,34 @@ func registerTagTools(s *server.MCPServer, r *registry) {
 
 func tagListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
+		if req.GetBool("all", false) {
+			list, err := r.allTags(ctx, wsID)
+			if err != nil {
+				return apiErrorResult("list tags", err, "", wsID), nil
+			}
+			return resultJSON(tagListOutput{Tags: list.items, Truncated: list.truncated})
+		}
+
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		tags, err := r.client.GetTags(wsID, page, pageSize)
+		tags, err := r.client(ctx).GetTags(ctx, wsID, page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list tags: %v", err)), nil
+			return apiErrorResult("list tags", err, "", wsID), nil
 		}
 
-		return resultJSON(map[string]any{"tags": tags})
+		return resultJSON(tagListOutput{Tags: tags})
 	}
 }
 
 func tagCreateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -81,10 +96,20 @@ func tagCreateHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("name is required"), nil
 		}
 
-		tag, err := r.client.CreateTag(wsID, clockify.CreateTagRequest{Name: name})
+		createReq := clockify.CreateTagRequest{Name: name}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "CreateTag",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: createReq,
+			})
+		}
+
+		tag, err := r.client(ctx).CreateTag(ctx, wsID, createReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create tag: %v", err)), nil
+			return apiErrorResult("create tag", err, "", wsID), nil
 		}
+		r.cache(ctx).invalidate(tagsKey(wsID))
 
 		return resultJSON(tag)
 	}
@@ -92,7 +117,7 @@ func tagCreateHandler(r *registry) server.ToolHandlerFunc {
 
 func tagUpdateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -112,10 +137,24 @@ func tagUpdateHandler(r *registry) server.ToolHandlerFunc {
 			updateReq.Archived = &a
 		}
 
-		tag, err := r.client.UpdateTag(wsID, tagID, updateReq)
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetTag(ctx, wsID, tagID)
+			if err != nil {
+				return apiErrorResult("get tag", err, "tag "+tagID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "UpdateTag",
+				Args:    map[string]string{"workspace_id": wsID, "tag_id": tagID},
+				Request: updateReq,
+				Current: current,
+			})
+		}
+
+		tag, err := r.client(ctx).UpdateTag(ctx, wsID, tagID, updateReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to update tag: %v", err)), nil
+			return apiErrorResult("update tag", err, "tag "+tagID, wsID), nil
 		}
+		r.cache(ctx).invalidate(tagsKey(wsID))
 
 		return resultJSON(tag)
 	}
@@ -123,7 +162,7 @@ func tagUpdateHandler(r *registry) server.ToolHandlerFunc {
 
 func tagDeleteHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -133,9 +172,22 @@ func tagDeleteHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("tag_id is required"), nil
 		}
 
-		if err := r.client.DeleteTag(wsID, tagID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete tag: %v", err)), nil
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetTag(ctx, wsID, tagID)
+			if err != nil {
+				return apiErrorResult("get tag", err, "tag "+tagID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "DeleteTag",
+				Args:    map[string]string{"workspace_id": wsID, "tag_id": tagID},
+				Current: current,
+			})
+		}
+
+		if err := r.client(ctx).DeleteTag(ctx, wsID, tagID); err != nil {
+			return apiErrorResult("delete tag", err, "tag "+tagID, wsID), nil
 		}
+		r.cache(ctx).invalidate(tagsKey(wsID))
 
 		return mcp.NewToolResultText("Tag deleted successfully."), nil
 	}
@@ -2,7 +2,6 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -10,10 +9,14 @@ import (
 )
 
 func registerTaskTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_task_list",
-			mcp.WithDescription("List tasks for a project (paginated, default page 1, page_size 50)"),
-			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+			mcp.WithDescription("List tasks for a project (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List tasks"),
+			mcp.WithOutputSchema[taskListOutput](),
+			mcp.WithString("project_id", mcp.Description("Project ID (project_id or project is required)")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of tasks per page (default 50)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -21,10 +24,13 @@ func registerTaskTools(s *server.MCPServer, r *registry) {
 		taskListHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_task_create",
 			mcp.WithDescription("Create a new task in a project"),
-			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+			createTool("Create task"),
+			mcp.WithOutputSchema[clockify.Task](),
+			mcp.WithString("project_id", mcp.Description("Project ID (project_id or project is required)")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
 			mcp.WithString("name", mcp.Required(), mcp.Description("Task name")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the task is billable")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -32,10 +38,13 @@ func registerTaskTools(s *server.MCPServer, r *registry) {
 		taskCreateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_task_update",
 			mcp.WithDescription("Update a task"),
-			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+			updateTool("Update task"),
+			mcp.WithOutputSchema[clockify.Task](),
+			mcp.WithString("project_id", mcp.Description("Project ID (project_id or project is required)")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
 			mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID to update")),
 			mcp.WithString("name", mcp.Description("New task name")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the task is billable")),
@@ -45,10 +54,12 @@ func registerTaskTools(s *server.MCPServer, r *registry) {
 		taskUpdateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_task_delete",
 			mcp.WithDescription("Delete a task"),
-			mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+			deleteTool("Delete task"),
+			mcp.WithString("project_id", mcp.Description("Project ID (project_id or project is required)")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
 			mcp.WithString("task_id", mcp.Required(), mcp.Description("Task ID to delete")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
@@ -58,51 +69,75 @@ func registerTaskTools(s *server.MCPServer, r *registry) {
 
 func taskListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		projectID, err := req.RequireString("project_id")
+		projectID, err := r.projectArg(ctx, req, wsID)
 		if err != nil {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID == "" {
+			return mcp.NewToolResultError("project_id or project is required"), nil
+		}
+
+		if req.GetBool("all", false) {
+			tasks, truncated, err := clockify.Collect(r.client(ctx).AllTasks(ctx, wsID, projectID))
+			if err != nil {
+				return apiErrorResult("list tasks", err, "project "+projectID, wsID), nil
+			}
+			return resultJSON(taskListOutput{Tasks: tasks, Truncated: truncated})
 		}
 
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		tasks, err := r.client.GetTasks(wsID, projectID, page, pageSize)
+		tasks, err := r.client(ctx).GetTasks(ctx, wsID, projectID, page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list tasks: %v", err)), nil
+			return apiErrorResult("list tasks", err, "project "+projectID, wsID), nil
 		}
 
-		return resultJSON(map[string]any{"tasks": tasks})
+		return resultJSON(taskListOutput{Tasks: tasks})
 	}
 }
 
 func taskCreateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		projectID, err := req.RequireString("project_id")
+		projectID, err := r.projectArg(ctx, req, wsID)
 		if err != nil {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID == "" {
+			return mcp.NewToolResultError("project_id or project is required"), nil
 		}
 		name, err := req.RequireString("name")
 		if err != nil {
 			return mcp.NewToolResultError("name is required"), nil
 		}
 
-		task, err := r.client.CreateTask(wsID, projectID, clockify.CreateTaskRequest{
+		createReq := clockify.CreateTaskRequest{
 			Name:     name,
 			Billable: req.GetBool("billable", false),
-		})
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "CreateTask",
+				Args:    map[string]string{"workspace_id": wsID, "project_id": projectID},
+				Request: createReq,
+			})
+		}
+
+		task, err := r.client(ctx).CreateTask(ctx, wsID, projectID, createReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create task: %v", err)), nil
+			return apiErrorResult("create task", err, "project "+projectID, wsID), nil
 		}
+		r.cache(ctx).invalidate(tasksKey(wsID, projectID))
 
 		return resultJSON(task)
 	}
@@ -110,14 +145,17 @@ func taskCreateHandler(r *registry) server.ToolHandlerFunc {
 
 func taskUpdateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		projectID, err := req.RequireString("project_id")
+		projectID, err := r.projectArg(ctx, req, wsID)
 		if err != nil {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID == "" {
+			return mcp.NewToolResultError("project_id or project is required"), nil
 		}
 		taskID, err := req.RequireString("task_id")
 		if err != nil {
@@ -135,10 +173,24 @@ func taskUpdateHandler(r *registry) server.ToolHandlerFunc {
 			updateReq.Billable = &b
 		}
 
-		task, err := r.client.UpdateTask(wsID, projectID, taskID, updateReq)
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetTask(ctx, wsID, projectID, taskID)
+			if err != nil {
+				return apiErrorResult("get task", err, "task "+taskID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "UpdateTask",
+				Args:    map[string]string{"workspace_id": wsID, "project_id": projectID, "task_id": taskID},
+				Request: updateReq,
+				Current: current,
+			})
+		}
+
+		task, err := r.client(ctx).UpdateTask(ctx, wsID, projectID, taskID, updateReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to update task: %v", err)), nil
+			return apiErrorResult("update task", err, "task "+taskID, wsID), nil
 		}
+		r.cache(ctx).invalidate(tasksKey(wsID, projectID))
 
 		return resultJSON(task)
 	}
@@ -146,23 +198,39 @@ func taskUpdateHandler(r *registry) server.ToolHandlerFunc {
 
 func taskDeleteHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		projectID, err := req.RequireString("project_id")
+		projectID, err := r.projectArg(ctx, req, wsID)
 		if err != nil {
-			return mcp.NewToolResultError("project_id is required"), nil
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID == "" {
+			return mcp.NewToolResultError("project_id or project is required"), nil
 		}
 		taskID, err := req.RequireString("task_id")
 		if err != nil {
 			return mcp.NewToolResultError("task_id is required"), nil
 		}
 
-		if err := r.client.DeleteTask(wsID, projectID, taskID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete task: %v", err)), nil
+		if r.isDryRun(req) {
+			current, err := r.client(ctx).GetTask(ctx, wsID, projectID, taskID)
+			if err != nil {
+				return apiErrorResult("get task", err, "task "+taskID, wsID), nil
+			}
+			return dryRunResult(dryRunOutput{
+				Call:    "DeleteTask",
+				Args:    map[string]string{"workspace_id": wsID, "project_id": projectID, "task_id": taskID},
+				Current: current,
+			})
+		}
+
+		if err := r.client(ctx).DeleteTask(ctx, wsID, projectID, taskID); err != nil {
+			return apiErrorResult("delete task", err, "task "+taskID, wsID), nil
 		}
+		r.cache(ctx).invalidate(tasksKey(wsID, projectID))
 
 		return mcp.NewToolResultText("Task deleted successfully."), nil
 	}
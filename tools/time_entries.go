@@ -2,21 +2,26 @@ package tools
 
 import (
 	"context"
-	"fmt"
 	"net/url"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
 )
 
 func registerTimeEntryTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_time_entry_list",
-			mcp.WithDescription("List time entries for the current user (paginated, default page 1, page_size 50)"),
-			mcp.WithString("start", mcp.Description("Start date filter (ISO 8601, e.g. 2024-01-01T00:00:00Z)")),
-			mcp.WithString("end", mcp.Description("End date filter (ISO 8601)")),
+			mcp.WithDescription("List time entries for the current user (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List time entries"),
+			mcp.WithOutputSchema[timeEntryListOutput](),
+			mcp.WithString("range", mcp.Description("Period to list, e.g. 'today', 'last week', 'this month', 'last 7 days'")),
+			mcp.WithString("start", mcp.Description("Start date filter (ISO 8601 or natural, e.g. 'yesterday', 'monday 9am'); overrides the start of range")),
+			mcp.WithString("end", mcp.Description("End date filter (ISO 8601 or natural); overrides the end of range")),
 			mcp.WithString("project_id", mcp.Description("Filter by project ID")),
+			mcp.WithString("project", mcp.Description("Filter by project name (alternative to project_id)")),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of entries per page (default 50)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -24,40 +29,54 @@ func registerTimeEntryTools(s *server.MCPServer, r *registry) {
 		timeEntryListHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_time_entry_create",
 			mcp.WithDescription("Create a manual time entry"),
-			mcp.WithString("start", mcp.Required(), mcp.Description("Start time (ISO 8601)")),
-			mcp.WithString("end", mcp.Required(), mcp.Description("End time (ISO 8601)")),
+			createTool("Create time entry"),
+			mcp.WithOutputSchema[clockify.TimeEntry](),
+			mcp.WithString("start", mcp.Required(), mcp.Description("Start time (ISO 8601 or natural, e.g. 'yesterday 2pm'), or a whole interval such as 'yesterday 2-4pm'")),
+			mcp.WithString("end", mcp.Description("End time (ISO 8601 or natural); a bare time such as '4pm' uses the start date")),
+			mcp.WithString("duration", mcp.Description("Duration instead of end, e.g. '1h30m', '90 minutes', '1:30'")),
 			mcp.WithString("description", mcp.Description("Entry description")),
 			mcp.WithString("project_id", mcp.Description("Project ID")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
 			mcp.WithString("task_id", mcp.Description("Task ID")),
+			mcp.WithString("task", mcp.Description("Task name within the project (alternative to task_id)")),
 			mcp.WithArray("tag_ids", mcp.Description("Tag IDs"), mcp.WithStringItems()),
+			mcp.WithArray("tags", mcp.Description("Tag names (alternative to tag_ids)"), mcp.WithStringItems()),
+			mcp.WithBoolean("create_missing", mcp.Description("Create tags named in tags that do not exist yet")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the entry is billable")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		timeEntryCreateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_time_entry_update",
-			mcp.WithDescription("Update an existing time entry"),
+			mcp.WithDescription("Update an existing time entry. Only the fields passed are changed; the rest are kept. Returns the updated entry and the changed fields."),
+			updateTool("Update time entry"),
+			mcp.WithOutputSchema[timeEntryUpdateOutput](),
 			mcp.WithString("entry_id", mcp.Required(), mcp.Description("Time entry ID to update")),
-			mcp.WithString("start", mcp.Required(), mcp.Description("Start time (ISO 8601)")),
-			mcp.WithString("end", mcp.Description("End time (ISO 8601)")),
-			mcp.WithString("description", mcp.Description("Entry description")),
-			mcp.WithString("project_id", mcp.Description("Project ID")),
-			mcp.WithString("task_id", mcp.Description("Task ID")),
-			mcp.WithArray("tag_ids", mcp.Description("Tag IDs"), mcp.WithStringItems()),
+			mcp.WithString("start", mcp.Description("Start time (ISO 8601 or natural, e.g. 'yesterday 2pm')")),
+			mcp.WithString("end", mcp.Description("End time (ISO 8601 or natural); a bare time such as '5pm' uses the start date")),
+			mcp.WithString("description", mcp.Description("Entry description (empty string clears it)")),
+			mcp.WithString("project_id", mcp.Description("Project ID (empty string clears it; changing the project clears the task)")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
+			mcp.WithString("task_id", mcp.Description("Task ID (empty string clears it)")),
+			mcp.WithString("task", mcp.Description("Task name within the project (alternative to task_id)")),
+			mcp.WithArray("tag_ids", mcp.Description("Tag IDs, replacing the current tags"), mcp.WithStringItems()),
+			mcp.WithArray("tags", mcp.Description("Tag names (alternative to tag_ids)"), mcp.WithStringItems()),
+			mcp.WithBoolean("create_missing", mcp.Description("Create tags named in tags that do not exist yet")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the entry is billable")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		timeEntryUpdateHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_time_entry_delete",
 			mcp.WithDescription("Delete a time entry"),
+			deleteTool("Delete time entry"),
 			mcp.WithString("entry_id", mcp.Required(), mcp.Description("Time entry ID to delete")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
@@ -67,75 +86,110 @@ func registerTimeEntryTools(s *server.MCPServer, r *registry) {
 
 func timeEntryListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		user, err := r.client.GetCurrentUser()
+		user, err := r.currentUser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get current user: %v", err)), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
 
+		p, err := r.parser(ctx)
+		if err != nil {
+			return apiErrorResult("get current user", err, "", ""), nil
+		}
+		start, end, err := filterRange(p, req, false)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 		params := url.Values{}
-		if start := req.GetString("start", ""); start != "" {
+		if start != "" {
 			params.Set("start", start)
 		}
-		if end := req.GetString("end", ""); end != "" {
+		if end != "" {
 			params.Set("end", end)
 		}
-		if projectID := req.GetString("project_id", ""); projectID != "" {
+		projectID, err := r.projectArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+		if projectID != "" {
 			params.Set("project", projectID)
 		}
 
+		if req.GetBool("all", false) {
+			entries, truncated, err := clockify.Collect(r.client(ctx).AllTimeEntries(ctx, wsID, user.ID, params))
+			if err != nil {
+				return apiErrorResult("list time entries", err, "", wsID), nil
+			}
+			return resultJSON(timeEntryListOutput{Entries: localEntries(entries, p.Location), Truncated: truncated})
+		}
+
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		entries, err := r.client.GetTimeEntries(wsID, user.ID, params, page, pageSize)
+		entries, err := r.client(ctx).GetTimeEntries(ctx, wsID, user.ID, params, page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list time entries: %v", err)), nil
+			return apiErrorResult("list time entries", err, "", wsID), nil
 		}
 
-		return resultJSON(map[string]any{"entries": entries})
+		return resultJSON(timeEntryListOutput{Entries: localEntries(entries, p.Location)})
 	}
 }
 
 func timeEntryCreateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		start, err := req.RequireString("start")
+		p, err := r.parser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError("start is required"), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
-		end, err := req.RequireString("end")
+		start, end, err := entryInterval(p, req)
 		if err != nil {
-			return mcp.NewToolResultError("end is required"), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		entry, err := r.client.CreateTimeEntry(wsID, clockify.CreateTimeEntryRequest{
+		refs, err := r.entryRefsArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+
+		createReq := clockify.CreateTimeEntryRequest{
 			Start:       start,
 			End:         end,
 			Description: req.GetString("description", ""),
-			ProjectID:   req.GetString("project_id", ""),
-			TaskID:      req.GetString("task_id", ""),
-			TagIDs:      req.GetStringSlice("tag_ids", nil),
+			ProjectID:   refs.projectID,
+			TaskID:      refs.taskID,
+			TagIDs:      refs.tagIDs,
 			Billable:    req.GetBool("billable", false),
-		})
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "CreateTimeEntry",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: createReq,
+				NewTags: refs.newTags,
+			})
+		}
+
+		entry, err := r.client(ctx).CreateTimeEntry(ctx, wsID, createReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to create time entry: %v", err)), nil
+			return apiErrorResult("create time entry", err, "", wsID), nil
 		}
 
-		return resultJSON(entry)
+		return resultJSON(localEntry(*entry, p.Location))
 	}
 }
 
 func timeEntryUpdateHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -144,31 +198,112 @@ func timeEntryUpdateHandler(r *registry) server.ToolHandlerFunc {
 		if err != nil {
 			return mcp.NewToolResultError("entry_id is required"), nil
 		}
-		start, err := req.RequireString("start")
+
+		args := req.GetArguments()
+		supplied := func(names ...string) bool {
+			for _, name := range names {
+				if _, ok := args[name]; ok {
+					return true
+				}
+			}
+			return false
+		}
+		if !supplied("start", "end", "description", "project_id", "project", "task_id", "task", "tag_ids", "tags", "billable") {
+			return mcp.NewToolResultError("nothing to update: pass at least one field to change"), nil
+		}
+
+		p, err := r.parser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError("start is required"), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
 
-		entry, err := r.client.UpdateTimeEntry(wsID, entryID, clockify.UpdateTimeEntryRequest{
-			Start:       start,
-			End:         req.GetString("end", ""),
-			Description: req.GetString("description", ""),
-			ProjectID:   req.GetString("project_id", ""),
-			TaskID:      req.GetString("task_id", ""),
-			TagIDs:      req.GetStringSlice("tag_ids", nil),
-			Billable:    req.GetBool("billable", false),
-		})
+		existing, err := r.client(ctx).GetTimeEntry(ctx, wsID, entryID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to update time entry: %v", err)), nil
+			return apiErrorResult("get time entry", err, "time entry "+entryID, wsID), nil
+		}
+		update := updateRequestFrom(*existing)
+
+		// A bare end time such as "5pm" belongs to the (new) start's day.
+		startArg := req.GetString("start", "")
+		if startArg != "" {
+			if update.Start, err = timeArg(p, req, "start"); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		if endArg := req.GetString("end", ""); endArg != "" {
+			if startArg == "" {
+				startArg = update.Start
+			}
+			_, end, err := p.Interval(startArg + " to " + endArg)
+			if err != nil {
+				return mcp.NewToolResultError("invalid end: " + err.Error()), nil
+			}
+			update.End = timeexpr.Format(end)
+		}
+		if supplied("description") {
+			update.Description = req.GetString("description", "")
+		}
+		if supplied("billable") {
+			update.Billable = req.GetBool("billable", false)
+		}
+		if supplied("project_id", "project") {
+			if update.ProjectID, err = r.projectArg(ctx, req, wsID); err != nil {
+				return resolveErrorResult(err, wsID), nil
+			}
+			// The old task belongs to the old project.
+			if update.ProjectID != existing.ProjectID {
+				update.TaskID = ""
+			}
+		}
+		if supplied("task_id", "task") {
+			if update.TaskID, err = r.taskArg(ctx, req, wsID, update.ProjectID); err != nil {
+				return resolveErrorResult(err, wsID), nil
+			}
+		}
+		var newTags []string
+		if supplied("tag_ids", "tags") {
+			if update.TagIDs, newTags, err = r.tagsArg(ctx, req, wsID); err != nil {
+				return resolveErrorResult(err, wsID), nil
+			}
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "UpdateTimeEntry",
+				Args:    map[string]string{"workspace_id": wsID, "entry_id": entryID},
+				Request: update,
+				Current: localEntry(*existing, p.Location),
+				NewTags: newTags,
+			})
 		}
 
-		return resultJSON(entry)
+		entry, err := r.client(ctx).UpdateTimeEntry(ctx, wsID, entryID, update)
+		if err != nil {
+			return apiErrorResult("update time entry", err, "time entry "+entryID, wsID), nil
+		}
+
+		return resultJSON(timeEntryUpdateOutput{
+			Entry:   localEntry(*entry, p.Location),
+			Changes: entryChanges(*existing, *entry, p.Location),
+		})
+	}
+}
+
+// updateRequestFrom returns the update request that rewrites e unchanged.
+func updateRequestFrom(e clockify.TimeEntry) clockify.UpdateTimeEntryRequest {
+	return clockify.UpdateTimeEntryRequest{
+		Start:       e.TimeInterval.Start,
+		End:         e.TimeInterval.End,
+		Description: e.Description,
+		ProjectID:   e.ProjectID,
+		TaskID:      e.TaskID,
+		TagIDs:      e.TagIDs,
+		Billable:    e.Billable,
 	}
 }
 
 func timeEntryDeleteHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
@@ -178,8 +313,27 @@ func timeEntryDeleteHandler(r *registry) server.ToolHandlerFunc {
 			return mcp.NewToolResultError("entry_id is required"), nil
 		}
 
-		if err := r.client.DeleteTimeEntry(wsID, entryID); err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to delete time entry: %v", err)), nil
+		if r.isDryRun(req) {
+			existing, err := r.client(ctx).GetTimeEntry(ctx, wsID, entryID)
+			if err != nil {
+				return apiErrorResult("get time entry", err, "time entry "+entryID, wsID), nil
+			}
+			loc, _ := r.userLocation(ctx)
+			return dryRunResult(dryRunOutput{
+				Call:    "DeleteTimeEntry",
+				Args:    map[string]string{"workspace_id": wsID, "entry_id": entryID},
+				Current: localEntry(*existing, loc),
+			})
+		}
+
+		if result := r.confirmDelete(ctx, req, "clockify_time_entry_delete", wsID, entryID, func() (any, *mcp.CallToolResult) {
+			return r.timeEntryDeletePreview(ctx, wsID, entryID)
+		}); result != nil {
+			return result, nil
+		}
+
+		if err := r.client(ctx).DeleteTimeEntry(ctx, wsID, entryID); err != nil {
+			return apiErrorResult("delete time entry", err, "time entry "+entryID, wsID), nil
 		}
 
 		return mcp.NewToolResultText("Time entry deleted successfully."), nil
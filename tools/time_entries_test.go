@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// entryServer serves a single stored time entry and applies PUTs to it.
+type entryServer struct {
+	entry clockify.TimeEntry
+	puts  []clockify.UpdateTimeEntryRequest
+}
+
+func (f *entryServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		w.Write([]byte(`{"id":"u1"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/projects":
+		w.Write([]byte(`[{"id":"p1","name":"Website"},{"id":"p2","name":"Internal"}]`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/time-entries/e1":
+		json.NewEncoder(w).Encode(f.entry)
+	case r.Method == http.MethodPut && r.URL.Path == "/workspaces/ws1/time-entries/e1":
+		var req clockify.UpdateTimeEntryRequest
+		b, _ := io.ReadAll(r.Body)
+		json.Unmarshal(b, &req)
+		f.puts = append(f.puts, req)
+		f.entry = clockify.TimeEntry{
+			ID:           "e1",
+			Description:  req.Description,
+			ProjectID:    req.ProjectID,
+			TaskID:       req.TaskID,
+			TagIDs:       req.TagIDs,
+			Billable:     req.Billable,
+			TimeInterval: clockify.TimeInterval{Start: req.Start, End: req.End},
+		}
+		json.NewEncoder(w).Encode(f.entry)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func storedEntry() clockify.TimeEntry {
+	return clockify.TimeEntry{
+		ID:           "e1",
+		Description:  "Standup",
+		ProjectID:    "p1",
+		TaskID:       "k1",
+		TagIDs:       []string{"g1"},
+		Billable:     true,
+		TimeInterval: clockify.TimeInterval{Start: "2024-03-12T09:00:00Z", End: "2024-03-12T09:15:00Z"},
+	}
+}
+
+func TestTimeEntryUpdate_KeepsOmittedFields(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timeEntryUpdateHandler(r), map[string]any{"entry_id": "e1", "description": "Daily standup"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+
+	if len(fake.puts) != 1 {
+		t.Fatalf("expected one PUT, got %d", len(fake.puts))
+	}
+	want := updateRequestFrom(storedEntry())
+	want.Description = "Daily standup"
+	got := fake.puts[0]
+	if got.Start != want.Start || got.End != want.End || got.Description != want.Description ||
+		got.ProjectID != want.ProjectID || got.TaskID != want.TaskID || !got.Billable ||
+		strings.Join(got.TagIDs, ",") != "g1" {
+		t.Fatalf("got request %+v, want %+v", got, want)
+	}
+
+	var payload struct {
+		Changes map[string]fieldChange `json:"changes"`
+	}
+	json.Unmarshal([]byte(resultText(t, result)), &payload)
+	if len(payload.Changes) != 1 || payload.Changes["description"] != (fieldChange{From: "Standup", To: "Daily standup"}) {
+		t.Fatalf("unexpected changes: %+v", payload.Changes)
+	}
+}
+
+func TestTimeEntryUpdate_ProjectChangeClearsTask(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timeEntryUpdateHandler(r), map[string]any{"entry_id": "e1", "project": "internal", "end": "10:00"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+
+	got := fake.puts[0]
+	if got.ProjectID != "p2" || got.TaskID != "" || got.End != "2024-03-12T10:00:00Z" {
+		t.Fatalf("unexpected request: %+v", got)
+	}
+	for _, field := range []string{`"project_id"`, `"task_id"`, `"end"`} {
+		if !strings.Contains(resultText(t, result), field) {
+			t.Errorf("changes missing %s: %s", field, resultText(t, result))
+		}
+	}
+}
+
+func TestTimeEntryUpdate_RequiresAField(t *testing.T) {
+	fake := &entryServer{entry: storedEntry()}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timeEntryUpdateHandler(r), map[string]any{"entry_id": "e1"})
+	if !result.IsError || len(fake.puts) != 0 {
+		t.Fatalf("expected an error without writes, got %s", resultText(t, result))
+	}
+}
@@ -0,0 +1,170 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
+)
+
+// userLocation returns the timezone times are interpreted and rendered in:
+// the configured override, else the timezone from the Clockify user settings,
+// else UTC. It returns UTC alongside any error, so callers that only render
+// times may ignore the error.
+func (r *registry) userLocation(ctx context.Context) (*time.Location, error) {
+	if r.location != nil {
+		return r.location, nil
+	}
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return time.UTC, err
+	}
+	if user.Settings.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(user.Settings.TimeZone)
+	if err != nil {
+		// Zone names this build does not know fall back to UTC rather than
+		// failing every time-related tool.
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// parser returns the parser for time arguments in the user's timezone.
+func (r *registry) parser(ctx context.Context) (timeexpr.Parser, error) {
+	loc, err := r.userLocation(ctx)
+	return timeexpr.Parser{Location: loc}, err
+}
+
+// localTime renders a Clockify timestamp in loc with its UTC offset. Values
+// that do not parse are returned unchanged.
+func localTime(s string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
+
+// localEntry returns e with its interval rendered in loc.
+func localEntry(e clockify.TimeEntry, loc *time.Location) clockify.TimeEntry {
+	e.TimeInterval.Start = localTime(e.TimeInterval.Start, loc)
+	e.TimeInterval.End = localTime(e.TimeInterval.End, loc)
+	return e
+}
+
+// localEntries returns a copy of entries rendered in loc.
+func localEntries(entries []clockify.TimeEntry, loc *time.Location) []clockify.TimeEntry {
+	out := make([]clockify.TimeEntry, len(entries))
+	for i, e := range entries {
+		out[i] = localEntry(e, loc)
+	}
+	return out
+}
+
+// timeArg parses the named argument as a time expression and returns it in
+// Clockify's format, or "" when the argument is absent.
+func timeArg(p timeexpr.Parser, req mcp.CallToolRequest, name string) (string, error) {
+	s := req.GetString(name, "")
+	if s == "" {
+		return "", nil
+	}
+	t, err := p.Time(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return timeexpr.Format(t), nil
+}
+
+// entryInterval resolves the start, end and duration arguments of a time
+// entry. start may be a whole interval ("yesterday 2-4pm"); otherwise end or
+// duration completes it. An end without a date takes the start's date.
+func entryInterval(p timeexpr.Parser, req mcp.CallToolRequest) (start, end string, err error) {
+	startArg := req.GetString("start", "")
+	endArg := req.GetString("end", "")
+	durationArg := req.GetString("duration", "")
+
+	var s, e time.Time
+	switch {
+	case startArg == "":
+		return "", "", fmt.Errorf("start is required")
+	case endArg != "" && durationArg != "":
+		return "", "", fmt.Errorf("pass either end or duration, not both")
+	case durationArg != "":
+		d, err := timeexpr.ParseDuration(durationArg)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid duration: %w", err)
+		}
+		if s, err = p.Time(startArg); err != nil {
+			return "", "", fmt.Errorf("invalid start: %w", err)
+		}
+		e = s.Add(d)
+	case endArg != "":
+		if s, e, err = p.Interval(startArg + " to " + endArg); err != nil {
+			return "", "", err
+		}
+	default:
+		if s, e, err = p.Interval(startArg); err != nil {
+			return "", "", fmt.Errorf("end or duration is required unless start is an interval: %w", err)
+		}
+	}
+	return timeexpr.Format(s), timeexpr.Format(e), nil
+}
+
+// filterRange resolves the range, start and end arguments of list and report
+// tools. start and end override the matching side of range; each may itself
+// be a period ("last week"), whose start or end is then used. With
+// inclusiveEnd, period ends become the last millisecond of the period, as the
+// reports API expects.
+func filterRange(p timeexpr.Parser, req mcp.CallToolRequest, inclusiveEnd bool) (start, end string, err error) {
+	var s, e time.Time
+	var periodEnd bool
+
+	if rangeArg := req.GetString("range", ""); rangeArg != "" {
+		if s, e, err = p.Range(rangeArg); err != nil {
+			return "", "", fmt.Errorf("invalid range: %w", err)
+		}
+		periodEnd = true
+	}
+	if startArg := req.GetString("start", ""); startArg != "" {
+		if ps, _, perr := p.Range(startArg); perr == nil {
+			s = ps
+		} else if s, err = p.Time(startArg); err != nil {
+			return "", "", fmt.Errorf("invalid start: %w", err)
+		}
+	}
+	if endArg := req.GetString("end", ""); endArg != "" {
+		if _, pe, perr := p.Range(endArg); perr == nil {
+			e, periodEnd = pe, true
+		} else if e, err = p.Time(endArg); err != nil {
+			return "", "", fmt.Errorf("invalid end: %w", err)
+		} else {
+			periodEnd = false
+		}
+	}
+
+	if !s.IsZero() {
+		start = timeexpr.Format(s)
+	}
+	if !e.IsZero() {
+		if periodEnd && inclusiveEnd {
+			end = inclusiveReportEnd(e)
+		} else {
+			end = timeexpr.Format(e)
+		}
+	}
+	if !s.IsZero() && !e.IsZero() && !e.After(s) {
+		return "", "", fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
+
+// inclusiveReportEnd renders the exclusive end of a period as its last
+// millisecond, the inclusive bound the reports API expects.
+func inclusiveReportEnd(end time.Time) string {
+	return end.Add(-time.Millisecond).UTC().Format("2006-01-02T15:04:05.000Z")
+}
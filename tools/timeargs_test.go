@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
+)
+
+// fixedParser works in UTC with the clock stopped at Wednesday 2024-03-13 15:00.
+func fixedParser() timeexpr.Parser {
+	now := time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC)
+	return timeexpr.Parser{Now: func() time.Time { return now }, Location: time.UTC}
+}
+
+func toolRequest(args map[string]any) mcp.CallToolRequest {
+	var req mcp.CallToolRequest
+	req.Params.Arguments = args
+	return req
+}
+
+func TestEntryInterval(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       map[string]any
+		start, end string
+	}{
+		{"iso", map[string]any{"start": "2024-03-01T09:00:00Z", "end": "2024-03-01T10:00:00Z"},
+			"2024-03-01T09:00:00Z", "2024-03-01T10:00:00Z"},
+		{"interval in start", map[string]any{"start": "yesterday 2-4pm"},
+			"2024-03-12T14:00:00Z", "2024-03-12T16:00:00Z"},
+		{"bare end takes start date", map[string]any{"start": "monday 9am", "end": "11:30"},
+			"2024-03-11T09:00:00Z", "2024-03-11T11:30:00Z"},
+		{"duration", map[string]any{"start": "yesterday 2pm", "duration": "1h30m"},
+			"2024-03-12T14:00:00Z", "2024-03-12T15:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := entryInterval(fixedParser(), toolRequest(tt.args))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if start != tt.start || end != tt.end {
+				t.Fatalf("got %s - %s, want %s - %s", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestEntryInterval_Invalid(t *testing.T) {
+	for _, args := range []map[string]any{
+		{},
+		{"start": "yesterday"},
+		{"start": "9am", "end": "10am", "duration": "1h"},
+		{"start": "9am", "duration": "forever"},
+		{"start": "4pm", "end": "2pm"},
+	} {
+		if start, end, err := entryInterval(fixedParser(), toolRequest(args)); err == nil {
+			t.Errorf("entryInterval(%v) = %s - %s, expected error", args, start, end)
+		}
+	}
+}
+
+func TestFilterRange(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         map[string]any
+		inclusiveEnd bool
+		start, end   string
+	}{
+		{"range", map[string]any{"range": "last week"}, false,
+			"2024-03-04T00:00:00Z", "2024-03-11T00:00:00Z"},
+		{"inclusive range end", map[string]any{"range": "last week"}, true,
+			"2024-03-04T00:00:00Z", "2024-03-10T23:59:59.999Z"},
+		{"start overrides range", map[string]any{"range": "this month", "start": "2024-03-05"}, false,
+			"2024-03-05T00:00:00Z", "2024-04-01T00:00:00Z"},
+		{"end day is inclusive", map[string]any{"start": "monday", "end": "yesterday"}, true,
+			"2024-03-11T00:00:00Z", "2024-03-12T23:59:59.999Z"},
+		{"explicit end is kept", map[string]any{"start": "monday", "end": "2024-03-12T18:00:00Z"}, true,
+			"2024-03-11T00:00:00Z", "2024-03-12T18:00:00Z"},
+		{"empty", map[string]any{}, true, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := filterRange(fixedParser(), toolRequest(tt.args), tt.inclusiveEnd)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if start != tt.start || end != tt.end {
+				t.Fatalf("got %s - %s, want %s - %s", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestReportSummary_UsesUserTimezone(t *testing.T) {
+	var body string
+	r := newTestRegistry(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id":"u1","settings":{"timeZone":"Europe/Prague"}}`))
+		case "/reports/workspaces/ws1/reports/summary":
+			b, _ := io.ReadAll(req.Body)
+			body = string(b)
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+
+	result := callTool(t, reportSummaryHandler(r), map[string]any{"range": "2024-03-01"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+
+	var got clockify.SummaryReportRequest
+	json.Unmarshal([]byte(body), &got)
+	if got.TimeZone != "Europe/Prague" ||
+		got.DateRangeStart != "2024-02-29T23:00:00Z" ||
+		got.DateRangeEnd != "2024-03-01T22:59:59.999Z" {
+		t.Fatalf("unexpected report request: %s", body)
+	}
+}
+
+func TestTimeEntryList_RendersInConfiguredTimezone(t *testing.T) {
+	r := newTestRegistry(t, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id":"u1","settings":{"timeZone":"Europe/Prague"}}`))
+		case "/workspaces/ws1/user/u1/time-entries":
+			w.Write([]byte(`[{"id":"e1","timeInterval":{"start":"2024-07-01T07:00:00Z","end":"2024-07-01T08:30:00Z"}}]`))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	r.location = time.FixedZone("PST", -8*3600)
+
+	result := callTool(t, timeEntryListHandler(r), map[string]any{})
+	text := resultText(t, result)
+	if !strings.Contains(text, `"start":"2024-06-30T23:00:00-08:00"`) || !strings.Contains(text, `"end":"2024-07-01T00:30:00-08:00"`) {
+		t.Fatalf("entries not rendered in override timezone: %s", text)
+	}
+}
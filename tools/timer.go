@@ -3,38 +3,88 @@ package tools
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/tedyno/ticktock-mcp/clockify"
+	"github.com/tedyno/ticktock-mcp/timeexpr"
 )
 
+// resumeSearchSize is how many recent entries clockify_timer_resume looks
+// through for the entry to resume.
+const resumeSearchSize = 50
+
 func registerTimerTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_timer_start",
 			mcp.WithDescription("Start a new timer in Clockify"),
+			createTool("Start timer"),
+			mcp.WithOutputSchema[clockify.TimeEntry](),
 			mcp.WithString("description", mcp.Description("Timer description")),
 			mcp.WithString("project_id", mcp.Description("Project ID")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
 			mcp.WithString("task_id", mcp.Description("Task ID")),
+			mcp.WithString("task", mcp.Description("Task name within the project (alternative to task_id)")),
 			mcp.WithArray("tag_ids", mcp.Description("Tag IDs"), mcp.WithStringItems()),
+			mcp.WithArray("tags", mcp.Description("Tag names (alternative to tag_ids)"), mcp.WithStringItems()),
+			mcp.WithBoolean("create_missing", mcp.Description("Create tags named in tags that do not exist yet")),
 			mcp.WithBoolean("billable", mcp.Description("Whether the entry is billable")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		timerStartHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_timer_stop",
 			mcp.WithDescription("Stop the currently running timer"),
+			stopTool("Stop timer"),
+			mcp.WithOutputSchema[clockify.TimeEntry](),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		timerStopHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
+		mcp.NewTool("clockify_timer_switch",
+			mcp.WithDescription("Stop the running timer and start a new one at the instant it stopped, with no gap or overlap. Starts the new timer alone if none is running"),
+			replaceTool("Switch timer"),
+			mcp.WithOutputSchema[timerSwitchOutput](),
+			mcp.WithString("description", mcp.Description("Description of the new timer")),
+			mcp.WithString("project_id", mcp.Description("Project ID")),
+			mcp.WithString("project", mcp.Description("Project name, matched case-insensitively (alternative to project_id)")),
+			mcp.WithString("task_id", mcp.Description("Task ID")),
+			mcp.WithString("task", mcp.Description("Task name within the project (alternative to task_id)")),
+			mcp.WithArray("tag_ids", mcp.Description("Tag IDs"), mcp.WithStringItems()),
+			mcp.WithArray("tags", mcp.Description("Tag names (alternative to tag_ids)"), mcp.WithStringItems()),
+			mcp.WithBoolean("create_missing", mcp.Description("Create tags named in tags that do not exist yet")),
+			mcp.WithBoolean("billable", mcp.Description("Whether the new entry is billable")),
+			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
+		),
+		timerSwitchHandler(r),
+	)
+
+	r.addTool(s,
+		mcp.NewTool("clockify_timer_resume",
+			mcp.WithDescription("Start a new timer with the description, project, task, tags and billable flag of a previous entry: the most recent stopped one, one matching description, or entry_id. With extend_within, the previous entry is continued instead when it ended less than that long ago"),
+			replaceTool("Resume timer"),
+			mcp.WithOutputSchema[timerResumeOutput](),
+			mcp.WithString("entry_id", mcp.Description("Time entry ID to resume")),
+			mcp.WithString("description", mcp.Description("Resume your most recent entry whose description contains this text, case-insensitively")),
+			mcp.WithString("extend_within", mcp.Description("Continue the previous entry instead of starting a new one when it is your latest entry and ended at most this long ago, e.g. '15m'")),
+			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
+		),
+		timerResumeHandler(r),
+	)
+
+	r.addTool(s,
 		mcp.NewTool("clockify_timer_current",
 			mcp.WithDescription("Get the currently running timer"),
+			readOnlyTool("Running timer"),
+			// No output schema: with no timer running the result is plain
+			// text, which no schema could describe.
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
 		),
 		timerCurrentHandler(r),
@@ -43,69 +93,325 @@ func registerTimerTools(s *server.MCPServer, r *registry) {
 
 func timerStartHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		entry, err := r.client.StartTimer(wsID, clockify.CreateTimeEntryRequest{
+		refs, err := r.entryRefsArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+
+		startReq := clockify.CreateTimeEntryRequest{
 			Start:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
 			Description: req.GetString("description", ""),
-			ProjectID:   req.GetString("project_id", ""),
-			TaskID:      req.GetString("task_id", ""),
-			TagIDs:      req.GetStringSlice("tag_ids", nil),
+			ProjectID:   refs.projectID,
+			TaskID:      refs.taskID,
+			TagIDs:      refs.tagIDs,
 			Billable:    req.GetBool("billable", false),
-		})
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "StartTimer",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: startReq,
+				NewTags: refs.newTags,
+			})
+		}
+
+		entry, err := r.client(ctx).StartTimer(ctx, wsID, startReq)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to start timer: %v", err)), nil
+			return apiErrorResult("start timer", err, "", wsID), nil
 		}
 
-		return resultJSON(entry)
+		loc, _ := r.userLocation(ctx)
+		return resultJSON(localEntry(*entry, loc))
 	}
 }
 
 func timerStopHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
+		if wsID == "" {
+			return mcp.NewToolResultError("workspace_id is required"), nil
+		}
+
+		user, err := r.currentUser(ctx)
+		if err != nil {
+			return apiErrorResult("get current user", err, "", ""), nil
+		}
+
+		if r.isDryRun(req) {
+			running, err := r.client(ctx).GetRunningTimer(ctx, wsID, user.ID)
+			if err != nil {
+				return apiErrorResult("get running timer", err, "", wsID), nil
+			}
+			if running == nil {
+				return mcp.NewToolResultError("No timer is currently running."), nil
+			}
+			loc, _ := r.userLocation(ctx)
+			return dryRunResult(dryRunOutput{
+				Call:    "StopTimer",
+				Args:    map[string]string{"workspace_id": wsID, "user_id": user.ID},
+				Current: localEntry(*running, loc),
+			})
+		}
+
+		entry, err := r.client(ctx).StopTimer(ctx, wsID, user.ID)
+		if err != nil {
+			return apiErrorResult("stop timer", err, "running timer", wsID), nil
+		}
+
+		loc, _ := r.userLocation(ctx)
+		return resultJSON(localEntry(*entry, loc))
+	}
+}
+
+func timerSwitchHandler(r *registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
+		if wsID == "" {
+			return mcp.NewToolResultError("workspace_id is required"), nil
+		}
+
+		user, err := r.currentUser(ctx)
+		if err != nil {
+			return apiErrorResult("get current user", err, "", ""), nil
+		}
+
+		// Resolve names before stopping anything, so a typo leaves the
+		// running timer alone.
+		refs, err := r.entryRefsArg(ctx, req, wsID)
+		if err != nil {
+			return resolveErrorResult(err, wsID), nil
+		}
+
+		running, err := r.client(ctx).GetRunningTimer(ctx, wsID, user.ID)
+		if err != nil {
+			return apiErrorResult("get running timer", err, "", wsID), nil
+		}
+
+		startReq := clockify.CreateTimeEntryRequest{
+			Start:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			Description: req.GetString("description", ""),
+			ProjectID:   refs.projectID,
+			TaskID:      refs.taskID,
+			TagIDs:      refs.tagIDs,
+			Billable:    req.GetBool("billable", false),
+		}
+		loc, _ := r.userLocation(ctx)
+		if r.isDryRun(req) {
+			out := dryRunOutput{
+				Call:    "StartTimer",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: startReq,
+				NewTags: refs.newTags,
+			}
+			if running != nil {
+				out.Call = "StopTimer, StartTimer"
+				out.Args["user_id"] = user.ID
+				out.Current = localEntry(*running, loc)
+			}
+			return dryRunResult(out)
+		}
+
+		var out timerSwitchOutput
+		if running != nil {
+			recordBefore(ctx, running.ID, running)
+			stopped, err := r.client(ctx).StopTimer(ctx, wsID, user.ID)
+			if err != nil {
+				return mcp.NewToolResultError("Failed to stop the running timer, so no new timer was started: " +
+					describeAPIError(err, "running timer", wsID)), nil
+			}
+			// The new timer starts exactly when the old one ended.
+			if stopped.TimeInterval.End != "" {
+				startReq.Start = stopped.TimeInterval.End
+			}
+			entry := localEntry(*stopped, loc)
+			out.Stopped = &entry
+		}
+
+		started, err := r.client(ctx).StartTimer(ctx, wsID, startReq)
+		if err != nil {
+			msg := "Failed to start the new timer: " + describeAPIError(err, "", wsID)
+			if out.Stopped != nil {
+				msg = fmt.Sprintf("Stopped the previous timer at %s, but failed to start the new one, so no timer is running now: %s",
+					out.Stopped.TimeInterval.End, describeAPIError(err, "", wsID))
+			}
+			return mcp.NewToolResultError(msg), nil
+		}
+		out.Started = localEntry(*started, loc)
+		return resultJSON(out)
+	}
+}
+
+func timerResumeHandler(r *registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		user, err := r.client.GetCurrentUser()
+		var extendWithin time.Duration
+		if v := req.GetString("extend_within", ""); v != "" {
+			d, err := timeexpr.ParseDuration(v)
+			if err != nil {
+				return mcp.NewToolResultError("invalid extend_within: " + err.Error()), nil
+			}
+			extendWithin = d
+		}
+
+		user, err := r.currentUser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get current user: %v", err)), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
+		loc, _ := r.userLocation(ctx)
 
-		entry, err := r.client.StopTimer(wsID, user.ID)
+		running, err := r.client(ctx).GetRunningTimer(ctx, wsID, user.ID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to stop timer: %v", err)), nil
+			return apiErrorResult("get running timer", err, "", wsID), nil
+		}
+		if running != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("A timer is already running (%q since %s). Stop it first, or use clockify_timer_switch.",
+				running.Description, localTime(running.TimeInterval.Start, loc))), nil
+		}
+
+		source, latest, errResult := r.resumeSource(ctx, req, wsID, user.ID, extendWithin > 0)
+		if errResult != nil {
+			return errResult, nil
+		}
+
+		if latest && extendWithin > 0 {
+			end, err := time.Parse(time.RFC3339, source.TimeInterval.End)
+			if err == nil && time.Since(end) <= extendWithin {
+				// An entry without an end is running again.
+				updateReq := clockify.UpdateTimeEntryRequest{
+					Start:       source.TimeInterval.Start,
+					Description: source.Description,
+					ProjectID:   source.ProjectID,
+					TaskID:      source.TaskID,
+					TagIDs:      source.TagIDs,
+					Billable:    source.Billable,
+				}
+				if r.isDryRun(req) {
+					return dryRunResult(dryRunOutput{
+						Call:    "UpdateTimeEntry",
+						Args:    map[string]string{"workspace_id": wsID, "entry_id": source.ID},
+						Request: updateReq,
+						Current: localEntry(*source, loc),
+					})
+				}
+				recordBefore(ctx, source.ID, source)
+				entry, err := r.client(ctx).UpdateTimeEntry(ctx, wsID, source.ID, updateReq)
+				if err != nil {
+					return apiErrorResult("continue time entry", err, "time entry "+source.ID, wsID), nil
+				}
+				return resultJSON(timerResumeOutput{Entry: localEntry(*entry, loc), ResumedFrom: source.ID, Extended: true})
+			}
 		}
 
-		return resultJSON(entry)
+		startReq := clockify.CreateTimeEntryRequest{
+			Start:       time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			Description: source.Description,
+			ProjectID:   source.ProjectID,
+			TaskID:      source.TaskID,
+			TagIDs:      source.TagIDs,
+			Billable:    source.Billable,
+		}
+		if r.isDryRun(req) {
+			return dryRunResult(dryRunOutput{
+				Call:    "StartTimer",
+				Args:    map[string]string{"workspace_id": wsID},
+				Request: startReq,
+			})
+		}
+
+		entry, err := r.client(ctx).StartTimer(ctx, wsID, startReq)
+		if err != nil {
+			return apiErrorResult("start timer", err, "", wsID), nil
+		}
+		return resultJSON(timerResumeOutput{Entry: localEntry(*entry, loc), ResumedFrom: source.ID})
+	}
+}
+
+// resumeSource finds the entry clockify_timer_resume copies: the one named
+// by entry_id, else the user's most recent stopped entry whose description
+// contains the description argument. latest reports whether it is also the
+// user's most recent entry; it is only worked out when needLatest is set.
+func (r *registry) resumeSource(ctx context.Context, req mcp.CallToolRequest, wsID, userID string, needLatest bool) (source *clockify.TimeEntry, latest bool, errResult *mcp.CallToolResult) {
+	entryID := req.GetString("entry_id", "")
+	search := req.GetString("description", "")
+
+	if entryID != "" {
+		entry, err := r.client(ctx).GetTimeEntry(ctx, wsID, entryID)
+		if err != nil {
+			return nil, false, apiErrorResult("get time entry", err, "time entry "+entryID, wsID)
+		}
+		if entry.TimeInterval.End == "" {
+			return nil, false, mcp.NewToolResultError(fmt.Sprintf("Time entry %s is still running.", entryID))
+		}
+		source = entry
+	} else {
+		params := url.Values{}
+		if search != "" {
+			params.Set("description", search)
+		}
+		entries, err := r.client(ctx).GetTimeEntries(ctx, wsID, userID, params, 1, resumeSearchSize)
+		if err != nil {
+			return nil, false, apiErrorResult("list time entries", err, "", wsID)
+		}
+		for i := range entries {
+			e := &entries[i]
+			if e.TimeInterval.End != "" && strings.Contains(strings.ToLower(e.Description), strings.ToLower(search)) {
+				source = e
+				break
+			}
+		}
+		if source == nil {
+			if search != "" {
+				return nil, false, mcp.NewToolResultError(fmt.Sprintf("No stopped time entry matching %q among your %d most recent.", search, resumeSearchSize))
+			}
+			return nil, false, mcp.NewToolResultError("No stopped time entry to resume.")
+		}
+		if search == "" {
+			// No timer runs, so the most recent stopped entry is the latest.
+			return source, true, nil
+		}
+	}
+
+	if !needLatest {
+		return source, false, nil
+	}
+	recent, err := r.client(ctx).GetTimeEntries(ctx, wsID, userID, nil, 1, 1)
+	if err != nil {
+		return nil, false, apiErrorResult("list time entries", err, "", wsID)
 	}
+	return source, len(recent) > 0 && recent[0].ID == source.ID, nil
 }
 
 func timerCurrentHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
-		user, err := r.client.GetCurrentUser()
+		user, err := r.currentUser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get current user: %v", err)), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
 
-		entry, err := r.client.GetRunningTimer(wsID, user.ID)
+		entry, err := r.client(ctx).GetRunningTimer(ctx, wsID, user.ID)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get running timer: %v", err)), nil
+			return apiErrorResult("get running timer", err, "", wsID), nil
 		}
 
 		if entry == nil {
 			return mcp.NewToolResultText("No timer is currently running."), nil
 		}
 
-		return resultJSON(entry)
+		loc, _ := r.userLocation(ctx)
+		return resultJSON(localEntry(*entry, loc))
 	}
 }
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// switchServer has a running timer, e1, that stops at 10:30.
+type switchServer struct {
+	fakeWorkspace
+	stops     int
+	failStart bool
+}
+
+func (f *switchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/user/u1/time-entries" && r.URL.Query().Get("in-progress") == "true":
+		if f.stops > 0 {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"e1","description":"Email","timeInterval":{"start":"2025-03-10T09:00:00Z"}}]`))
+	case r.Method == http.MethodPatch && r.URL.Path == "/workspaces/ws1/user/u1/time-entries":
+		f.stops++
+		w.Write([]byte(`{"id":"e1","description":"Email","timeInterval":{"start":"2025-03-10T09:00:00Z","end":"2025-03-10T10:30:00Z"}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws1/time-entries":
+		if f.failStart {
+			http.Error(w, `{"message":"Project is archived","code":501}`, http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		f.entryBodies = append(f.entryBodies, string(b))
+		var req clockify.CreateTimeEntryRequest
+		json.Unmarshal(b, &req)
+		json.NewEncoder(w).Encode(clockify.TimeEntry{ID: "e2", Description: req.Description, ProjectID: req.ProjectID,
+			TimeInterval: clockify.TimeInterval{Start: req.Start}})
+	default:
+		f.fakeWorkspace.ServeHTTP(w, r)
+	}
+}
+
+func TestTimerSwitch_StartsWhenPreviousStopped(t *testing.T) {
+	fake := &switchServer{}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerSwitchHandler(r), map[string]any{"description": "Review", "project": "internal"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if fake.stops != 1 || len(fake.entryBodies) != 1 {
+		t.Fatalf("expected one stop and one start, got %d and %d", fake.stops, len(fake.entryBodies))
+	}
+	var req clockify.CreateTimeEntryRequest
+	json.Unmarshal([]byte(fake.entryBodies[0]), &req)
+	if req.Start != "2025-03-10T10:30:00Z" || req.ProjectID != "p2" || req.Description != "Review" {
+		t.Fatalf("unexpected start request: %s", fake.entryBodies[0])
+	}
+
+	var out timerSwitchOutput
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if out.Stopped == nil || out.Stopped.ID != "e1" || out.Started.ID != "e2" {
+		t.Fatalf("unexpected output: %s", resultText(t, result))
+	}
+}
+
+func TestTimerSwitch_UnknownProjectLeavesTimerRunning(t *testing.T) {
+	fake := &switchServer{}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerSwitchHandler(r), map[string]any{"project": "nope"})
+	if !result.IsError {
+		t.Fatalf("expected an error, got %s", resultText(t, result))
+	}
+	if fake.stops != 0 {
+		t.Fatal("timer was stopped although the project did not resolve")
+	}
+}
+
+func TestTimerSwitch_ReportsFailedStart(t *testing.T) {
+	fake := &switchServer{failStart: true}
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerSwitchHandler(r), map[string]any{"description": "Review"})
+	if !result.IsError {
+		t.Fatalf("expected an error, got %s", resultText(t, result))
+	}
+	text := resultText(t, result)
+	if !strings.Contains(text, "Stopped the previous timer") || !strings.Contains(text, "failed to start the new one") {
+		t.Fatalf("error does not say which step failed: %s", text)
+	}
+}
+
+func TestTimerSwitch_FailedStartIsNotUndone(t *testing.T) {
+	fake := &switchServer{failStart: true}
+	r := newTestRegistry(t, fake)
+	r.journal = newTestJournal(t)
+
+	callTool(t, r.withAudit("clockify_timer_switch", timerSwitchHandler(r)), map[string]any{"description": "Review"})
+
+	// The only successful write stopped e1; undo must not take it for a
+	// created entry and delete it.
+	result := callTool(t, undoHandler(r), map[string]any{"confirm": true})
+	if !result.IsError || !strings.Contains(resultText(t, result), "failed partway") {
+		t.Fatalf("expected a refusal, got %s", resultText(t, result))
+	}
+}
+
+// resumeServer lists entries, newest first, and records the timers started
+// and entries updated.
+type resumeServer struct {
+	entries []clockify.TimeEntry
+	started []clockify.CreateTimeEntryRequest
+	updated []clockify.UpdateTimeEntryRequest
+}
+
+func (f *resumeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/user":
+		w.Write([]byte(`{"id":"u1"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/workspaces/ws1/user/u1/time-entries":
+		var out []clockify.TimeEntry
+		for _, e := range f.entries {
+			running := e.TimeInterval.End == ""
+			if r.URL.Query().Get("in-progress") == "true" && !running {
+				continue
+			}
+			if d := r.URL.Query().Get("description"); d != "" && !strings.Contains(strings.ToLower(e.Description), strings.ToLower(d)) {
+				continue
+			}
+			out = append(out, e)
+		}
+		if r.URL.Query().Get("page-size") == "1" && len(out) > 1 {
+			out = out[:1]
+		}
+		json.NewEncoder(w).Encode(out)
+	case r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws1/time-entries":
+		var req clockify.CreateTimeEntryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.started = append(f.started, req)
+		json.NewEncoder(w).Encode(clockify.TimeEntry{ID: "new", Description: req.Description, TimeInterval: clockify.TimeInterval{Start: req.Start}})
+	case r.Method == http.MethodPut && r.URL.Path == "/workspaces/ws1/time-entries/e1":
+		var req clockify.UpdateTimeEntryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.updated = append(f.updated, req)
+		json.NewEncoder(w).Encode(clockify.TimeEntry{ID: "e1", Description: req.Description, TimeInterval: clockify.TimeInterval{Start: req.Start}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newResumeServer has e1, which ended ago, and the older e2.
+func newResumeServer(ago time.Duration) *resumeServer {
+	end := time.Now().UTC().Add(-ago).Truncate(time.Second)
+	return &resumeServer{entries: []clockify.TimeEntry{
+		{ID: "e1", Description: "Code review", ProjectID: "p1", TaskID: "k1", TagIDs: []string{"g1"}, Billable: true,
+			TimeInterval: clockify.TimeInterval{Start: end.Add(-time.Hour).Format(time.RFC3339), End: end.Format(time.RFC3339)}},
+		{ID: "e2", Description: "Email", ProjectID: "p2",
+			TimeInterval: clockify.TimeInterval{Start: end.Add(-3 * time.Hour).Format(time.RFC3339), End: end.Add(-2 * time.Hour).Format(time.RFC3339)}},
+	}}
+}
+
+func TestTimerResume_CopiesMostRecentEntry(t *testing.T) {
+	fake := newResumeServer(time.Hour)
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerResumeHandler(r), map[string]any{"extend_within": "15m"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if len(fake.started) != 1 || len(fake.updated) != 0 {
+		t.Fatalf("expected one new timer, got %d started and %d updated", len(fake.started), len(fake.updated))
+	}
+	got := fake.started[0]
+	if got.Description != "Code review" || got.ProjectID != "p1" || got.TaskID != "k1" || strings.Join(got.TagIDs, ",") != "g1" || !got.Billable {
+		t.Fatalf("entry not copied: %+v", got)
+	}
+}
+
+func TestTimerResume_DescriptionSearch(t *testing.T) {
+	fake := newResumeServer(time.Minute)
+	r := newTestRegistry(t, fake)
+
+	// e2 ended long ago and is not the latest entry, so it is not extended.
+	result := callTool(t, timerResumeHandler(r), map[string]any{"description": "email", "extend_within": "1h"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	var out timerResumeOutput
+	json.Unmarshal([]byte(resultText(t, result)), &out)
+	if out.ResumedFrom != "e2" || out.Extended || len(fake.started) != 1 || fake.started[0].ProjectID != "p2" {
+		t.Fatalf("unexpected resume: %s", resultText(t, result))
+	}
+
+	result = callTool(t, timerResumeHandler(r), map[string]any{"description": "standup"})
+	if !result.IsError {
+		t.Fatalf("expected no match, got %s", resultText(t, result))
+	}
+}
+
+func TestTimerResume_ExtendsRecentEntry(t *testing.T) {
+	fake := newResumeServer(5 * time.Minute)
+	r := newTestRegistry(t, fake)
+	r.journal = newTestJournal(t)
+
+	result := callTool(t, r.withAudit("clockify_timer_resume", timerResumeHandler(r)), map[string]any{"extend_within": "15m"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if len(fake.updated) != 1 || len(fake.started) != 0 {
+		t.Fatalf("expected e1 to be continued, got %d updated and %d started", len(fake.updated), len(fake.started))
+	}
+	if got := fake.updated[0]; got.End != "" || got.Start != fake.entries[0].TimeInterval.Start {
+		t.Fatalf("unexpected update: %+v", got)
+	}
+
+	// The audit record holds e1 as it was, so undo can stop it again.
+	records, _ := r.journal.Query(audit.Query{})
+	if len(records) != 1 || records[0].EntityID != "e1" || !strings.Contains(string(records[0].Before), fake.entries[0].TimeInterval.End) {
+		t.Fatalf("unexpected audit record: %+v", records)
+	}
+}
+
+func TestTimerResume_RefusesWhileRunning(t *testing.T) {
+	fake := newResumeServer(time.Hour)
+	fake.entries[0].TimeInterval.End = ""
+	r := newTestRegistry(t, fake)
+
+	result := callTool(t, timerResumeHandler(r), map[string]any{})
+	if !result.IsError || !strings.Contains(resultText(t, result), "already running") {
+		t.Fatalf("expected a refusal, got %s", resultText(t, result))
+	}
+	if len(fake.started) != 0 {
+		t.Fatal("timer started while another was running")
+	}
+}
+
+func TestTimerSwitch_UndoResumesPreviousTimer(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	running := storedEntry()
+	running.TimeInterval.End = ""
+	fake.entries["e1"] = running
+
+	switchTimer := r.withAudit("clockify_timer_switch", timerSwitchHandler(r))
+	result := callTool(t, switchTimer, map[string]any{"description": "Review"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	stoppedAt := fake.entries["e1"].TimeInterval.End
+	if stoppedAt == "" || fake.entries["n1"].TimeInterval.Start != stoppedAt {
+		t.Fatalf("unexpected switch: %+v", fake.entries)
+	}
+
+	result = callTool(t, undoHandler(r), map[string]any{"confirm": true})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if _, ok := fake.entries["n1"]; ok {
+		t.Fatal("started timer was not deleted")
+	}
+	if got := fake.entries["e1"]; got.TimeInterval.End != "" || got.Description != "Standup" {
+		t.Fatalf("previous timer not running again: %+v", got)
+	}
+}
+
+func TestTimerSwitch_UndoRefusesEditedStoppedEntry(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	running := storedEntry()
+	running.TimeInterval.End = ""
+	fake.entries["e1"] = running
+
+	callTool(t, r.withAudit("clockify_timer_switch", timerSwitchHandler(r)), map[string]any{"description": "Review"})
+	e := fake.entries["e1"]
+	e.Description = "Standup and planning"
+	fake.entries["e1"] = e
+
+	result := callTool(t, undoHandler(r), map[string]any{"confirm": true})
+	if !result.IsError || !strings.Contains(resultText(t, result), "stopped time entry has changed since") {
+		t.Fatalf("expected a refusal, got %s", resultText(t, result))
+	}
+	if _, ok := fake.entries["n1"]; !ok {
+		t.Fatal("refused undo deleted the started timer")
+	}
+}
@@ -0,0 +1,550 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// undoToolName is left out of the audit wrapper: the undo tool records each
+// step itself, marked with the operation it reverted.
+const undoToolName = "clockify_undo"
+
+const (
+	defaultUndoCount = 1
+	maxUndoCount     = 50
+)
+
+// Undo actions, by what the original operation did.
+const (
+	undoDelete   = "delete"   // the operation created the entity
+	undoRestore  = "restore"  // the operation updated it
+	undoRecreate = "recreate" // the operation deleted it
+	undoResume   = "resume"   // the operation stopped a timer and started another
+)
+
+type undoOutput struct {
+	Preview bool       `json:"preview"`
+	Steps   []undoStep `json:"steps"`
+}
+
+// undoStep reverts one recorded operation. State is what a restore or
+// recreate writes back. Recreated entities get a new ID, NewID.
+type undoStep struct {
+	OperationID string          `json:"operation_id"`
+	Tool        string          `json:"tool"`
+	Time        time.Time       `json:"time"`
+	Entity      string          `json:"entity,omitempty"`
+	EntityID    string          `json:"entity_id,omitempty"`
+	Action      string          `json:"action,omitempty"`
+	State       json.RawMessage `json:"state,omitempty"`
+	Refused     string          `json:"refused,omitempty"`
+	Done        bool            `json:"done,omitempty"`
+	NewID       string          `json:"new_id,omitempty"`
+	Error       string          `json:"error,omitempty"`
+}
+
+func registerUndoTools(s *server.MCPServer, r *registry) {
+	if r.journal == nil {
+		return
+	}
+	r.addTool(s,
+		mcp.NewTool(undoToolName,
+			mcp.WithDescription("Revert your most recent changes recorded in the audit log, newest first: restore updated entities, recreate deleted ones (under new IDs) and delete created ones. Without confirm it only previews the plan. It refuses if any entity has changed since the operation."),
+			replaceTool("Undo changes"),
+			mcp.WithOutputSchema[undoOutput](),
+			mcp.WithNumber("count", mcp.Description("Number of most recent operations to revert (default 1, max 50)")),
+			mcp.WithString("operation_id", mcp.Description("ID of a single operation to revert, from clockify_audit_query (instead of count)")),
+			mcp.WithBoolean("confirm", mcp.Description("Carry out the undo; without it the plan is only previewed")),
+		),
+		undoHandler(r),
+	)
+}
+
+func undoHandler(r *registry) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		user, err := r.currentUser(ctx)
+		if err != nil {
+			return apiErrorResult("get current user", err, "", ""), nil
+		}
+		records, err := r.journal.Query(audit.Query{UserID: user.ID})
+		if err != nil {
+			return mcp.NewToolResultError("Failed to read audit log: " + err.Error()), nil
+		}
+		ops, err := undoSelection(records, req.GetString("operation_id", ""), req.GetInt("count", defaultUndoCount))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		out := undoOutput{Steps: r.planUndo(ctx, ops)}
+		if !req.GetBool("confirm", false) || r.isDryRun(req) {
+			out.Preview = true
+			return resultJSON(out)
+		}
+		for _, step := range out.Steps {
+			if step.Refused != "" {
+				return undoErrorResult(fmt.Sprintf("Nothing was undone: operation %s cannot be undone: %s.", step.OperationID, step.Refused), out), nil
+			}
+		}
+		if err := r.runUndo(ctx, ops, out.Steps, req.GetArguments()); err != nil {
+			return undoErrorResult("Undo stopped: "+err.Error()+". Steps marked done were reverted.", out), nil
+		}
+		return resultJSON(out)
+	}
+}
+
+// undoErrorResult reports a refused or failed undo with its steps.
+func undoErrorResult(msg string, out undoOutput) *mcp.CallToolResult {
+	b, _ := json.Marshal(out)
+	return mcp.NewToolResultError(msg + "\n" + string(b))
+}
+
+// undoSelection picks the records to revert, newest first: the one with
+// opID, or the count most recent changes not yet undone. records are the
+// caller's, newest first.
+func undoSelection(records []audit.Record, opID string, count int) ([]audit.Record, error) {
+	undone := map[string]bool{}
+	for _, rec := range records {
+		if rec.Undoes != "" {
+			undone[rec.Undoes] = true
+		}
+	}
+
+	if opID != "" {
+		for _, rec := range records {
+			if rec.ID != opID {
+				continue
+			}
+			switch {
+			case rec.Tool == undoToolName:
+				return nil, fmt.Errorf("operation %s is an undo and cannot itself be undone", opID)
+			case undone[rec.ID]:
+				return nil, fmt.Errorf("operation %s has already been undone", opID)
+			case !changedClockify(rec):
+				return nil, fmt.Errorf("operation %s did not change anything", opID)
+			}
+			return []audit.Record{rec}, nil
+		}
+		return nil, fmt.Errorf("operation %s not found in your audit log", opID)
+	}
+
+	count = max(1, min(count, maxUndoCount))
+	var ops []audit.Record
+	for _, rec := range records {
+		if rec.Tool == undoToolName || undone[rec.ID] || !changedClockify(rec) {
+			continue
+		}
+		ops = append(ops, rec)
+		if len(ops) == count {
+			break
+		}
+	}
+	if len(ops) == 0 {
+		return nil, errors.New("no changes to undo in your audit log")
+	}
+	return ops, nil
+}
+
+// changedClockify reports whether any write of rec succeeded.
+func changedClockify(rec audit.Record) bool {
+	for _, resp := range rec.Responses {
+		if resp.Status >= 200 && resp.Status < 300 {
+			return true
+		}
+	}
+	return false
+}
+
+// undoAction picks how to revert rec, or explains why it cannot be.
+func undoAction(rec audit.Record) (action, refused string) {
+	if rec.Entity == "" || rec.EntityID == "" {
+		return "", "it does not change a single entity"
+	}
+	// The last write of a call that failed partway may not be the entity
+	// the call was about, as when clockify_timer_switch stops a timer but
+	// fails to start the next.
+	if rec.Error != "" {
+		return "", "it failed partway through"
+	}
+	deleted := false
+	for _, resp := range rec.Responses {
+		if resp.Status >= 200 && resp.Status < 300 {
+			deleted = resp.Method == http.MethodDelete
+		}
+	}
+	switch {
+	case rec.Tool == "clockify_timer_switch" && len(rec.Before) > 0 && len(rec.After) > 0:
+		return undoResume, ""
+	case len(rec.Before) == 0 && len(rec.After) > 0:
+		return undoDelete, ""
+	case len(rec.Before) > 0 && len(rec.After) > 0:
+		return undoRestore, ""
+	case len(rec.Before) > 0 && deleted:
+		return undoRecreate, ""
+	}
+	return "", "the audit log lacks the state needed to revert it"
+}
+
+// planUndo works out the steps for ops, checking each entity against the
+// state the operation left. Earlier steps are taken into account, so
+// several operations on one entity can be reverted together.
+func (r *registry) planUndo(ctx context.Context, ops []audit.Record) []undoStep {
+	// expected holds entity states as the planned steps leave them; nil
+	// marks an entity that will no longer exist.
+	expected := map[string]json.RawMessage{}
+	steps := make([]undoStep, len(ops))
+	for i, rec := range ops {
+		step := undoStep{
+			OperationID: rec.ID,
+			Tool:        rec.Tool,
+			Time:        rec.Time,
+			Entity:      rec.Entity,
+			EntityID:    rec.EntityID,
+		}
+		step.Action, step.Refused = undoAction(rec)
+		if step.Action == undoResume && step.Refused == "" {
+			if step.Refused = r.planResume(ctx, rec, expected); step.Refused == "" {
+				step.State = rec.Before
+			}
+		} else if step.Refused == "" {
+			key := rec.Entity + ":" + rec.EntityID
+			current, ok := expected[key]
+			if !ok {
+				var err error
+				if current, err = r.currentState(ctx, rec); err != nil {
+					step.Refused = "its current state could not be read: " + describeAPIError(err, entitySubject(rec), rec.WorkspaceID)
+				}
+			}
+			if step.Refused == "" {
+				step.Refused = undoConflict(rec, step.Action, current)
+			}
+			if step.Refused == "" {
+				switch step.Action {
+				case undoDelete:
+					expected[key] = nil
+				case undoRestore:
+					step.State = rec.Before
+					expected[key] = rec.Before
+				case undoRecreate:
+					step.State = rec.Before
+				}
+			}
+		}
+		steps[i] = step
+	}
+	return steps
+}
+
+// planResume checks that a timer switch can be reverted: the timer it
+// started, in After, is unchanged, and the entry it stopped, in Before, has
+// changed only by being stopped. It updates expected as the undo will.
+func (r *registry) planResume(ctx context.Context, rec audit.Record, expected map[string]json.RawMessage) string {
+	started := rec
+	started.EntityID = entityID(rec.After)
+	state := func(rec audit.Record) (json.RawMessage, string) {
+		if current, ok := expected[rec.Entity+":"+rec.EntityID]; ok {
+			return current, ""
+		}
+		current, err := r.currentState(ctx, rec)
+		if err != nil {
+			return nil, "its current state could not be read: " + describeAPIError(err, entitySubject(rec), rec.WorkspaceID)
+		}
+		return current, ""
+	}
+
+	current, refused := state(started)
+	if refused != "" {
+		return refused
+	}
+	if refused := undoConflict(started, undoDelete, current); refused != "" {
+		return strings.Replace(refused, "the time entry", "the started time entry", 1)
+	}
+
+	if current, refused = state(rec); refused != "" {
+		return refused
+	}
+	if current == nil {
+		return "the stopped time entry no longer exists"
+	}
+	var was, now clockify.TimeEntry
+	json.Unmarshal(rec.Before, &was)
+	json.Unmarshal(current, &now)
+	if now.TimeInterval.End == "" {
+		return "the stopped time entry is running again"
+	}
+	now.TimeInterval.End, now.TimeInterval.Duration = was.TimeInterval.End, was.TimeInterval.Duration
+	nowJSON, _ := json.Marshal(now)
+	if !bytes.Equal(entityJSON(rec.Entity, nowJSON), entityJSON(rec.Entity, rec.Before)) {
+		return "the stopped time entry has changed since"
+	}
+
+	expected[started.Entity+":"+started.EntityID] = nil
+	expected[rec.Entity+":"+rec.EntityID] = rec.Before
+	return ""
+}
+
+// currentState fetches the entity rec changed, or returns nil if it no
+// longer exists.
+func (r *registry) currentState(ctx context.Context, rec audit.Record) (json.RawMessage, error) {
+	state, err := r.entityState(ctx, rec.Entity, rec.WorkspaceID, recordProjectID(rec), rec.EntityID)
+	if clockify.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(state)
+	return b, err
+}
+
+// recordProjectID returns the project of a task record.
+func recordProjectID(rec audit.Record) string {
+	var t struct {
+		ProjectID string `json:"projectId"`
+	}
+	if json.Unmarshal(rec.After, &t) != nil || t.ProjectID == "" {
+		json.Unmarshal(rec.Before, &t)
+	}
+	return t.ProjectID
+}
+
+// undoConflict explains why rec cannot be reverted from current, its
+// entity's present state, or returns "".
+func undoConflict(rec audit.Record, action string, current json.RawMessage) string {
+	subject := strings.ReplaceAll(rec.Entity, "_", " ")
+	if action == undoRecreate {
+		if current != nil {
+			return "the " + subject + " still exists"
+		}
+		return ""
+	}
+	if current == nil {
+		return "the " + subject + " no longer exists"
+	}
+	if !bytes.Equal(entityJSON(rec.Entity, current), entityJSON(rec.Entity, rec.After)) {
+		return "the " + subject + " has changed since"
+	}
+	if action == undoRestore && rec.Entity == "project" {
+		var before, after clockify.Project
+		json.Unmarshal(rec.Before, &before)
+		json.Unmarshal(rec.After, &after)
+		if before.ClientID == "" && after.ClientID != "" {
+			return "Clockify cannot remove the client it gave the project"
+		}
+	}
+	return ""
+}
+
+func entitySubject(rec audit.Record) string {
+	return strings.ReplaceAll(rec.Entity, "_", " ") + " " + rec.EntityID
+}
+
+// runUndo carries out planned steps in order, recording each in the
+// journal. It stops at the first failure.
+func (r *registry) runUndo(ctx context.Context, ops []audit.Record, steps []undoStep, args map[string]any) error {
+	for i := range steps {
+		step, rec := &steps[i], ops[i]
+
+		var writes writeLog
+		wctx := clockify.WithWriteObserver(ctx, writes.observe)
+		var err error
+		switch step.Action {
+		case undoDelete:
+			err = r.deleteEntity(wctx, rec.WorkspaceID, rec.Entity, rec.After)
+		case undoRestore:
+			err = r.restoreEntity(wctx, rec.WorkspaceID, rec.Entity, rec.Before)
+		case undoRecreate:
+			err = r.recreateEntity(wctx, rec.WorkspaceID, rec.Entity, rec.Before)
+		case undoResume:
+			// Only one timer can run, so the started one goes first.
+			if err = r.deleteEntity(wctx, rec.WorkspaceID, rec.Entity, rec.After); err == nil {
+				err = r.restoreEntity(wctx, rec.WorkspaceID, rec.Entity, rec.Before)
+			}
+		}
+		r.invalidateEntity(ctx, rec)
+
+		undoRec := audit.Record{
+			Tool:        undoToolName,
+			Args:        args,
+			WorkspaceID: rec.WorkspaceID,
+			Entity:      rec.Entity,
+			EntityID:    rec.EntityID,
+			Responses:   writes.responses,
+			Undoes:      rec.ID,
+		}
+		if step.Action != undoRecreate && step.Action != undoResume {
+			undoRec.Before = rec.After
+		}
+		if len(writes.after) > 0 {
+			undoRec.After = entityJSON(rec.Entity, writes.after)
+		}
+		if step.Action == undoRecreate && undoRec.After != nil {
+			step.NewID = entityID(undoRec.After)
+			undoRec.EntityID = step.NewID
+		}
+		if err != nil {
+			undoRec.Error = err.Error()
+			step.Error = describeAPIError(err, entitySubject(rec), rec.WorkspaceID)
+		}
+		// Only changes are recorded, so a failed step can be retried.
+		if changedClockify(undoRec) {
+			if jerr := r.appendRecord(ctx, &undoRec); jerr != nil && err == nil {
+				err = fmt.Errorf("operation %s was reverted but not recorded in the audit log: %w", rec.ID, jerr)
+				step.Done = true
+				step.Error = err.Error()
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("operation %s: %s", rec.ID, step.Error)
+		}
+		step.Done = true
+	}
+	return nil
+}
+
+// invalidateEntity drops cached listings the entity of rec appears in.
+func (r *registry) invalidateEntity(ctx context.Context, rec audit.Record) {
+	switch rec.Entity {
+	case "project":
+		r.cache(ctx).invalidate(projectsKey(rec.WorkspaceID))
+	case "task":
+		r.cache(ctx).invalidate(tasksKey(rec.WorkspaceID, recordProjectID(rec)))
+	case "tag":
+		r.cache(ctx).invalidate(tagsKey(rec.WorkspaceID))
+	case "client":
+		r.cache(ctx).invalidate(clientsKey(rec.WorkspaceID))
+	}
+}
+
+// deleteEntity deletes the entity whose recorded state is state.
+func (r *registry) deleteEntity(ctx context.Context, wsID, entity string, state json.RawMessage) error {
+	c := r.client(ctx)
+	id := entityID(state)
+	switch entity {
+	case "time_entry":
+		return c.DeleteTimeEntry(ctx, wsID, id)
+	case "project":
+		return c.DeleteProject(ctx, wsID, id)
+	case "task":
+		var t clockify.Task
+		json.Unmarshal(state, &t)
+		return c.DeleteTask(ctx, wsID, t.ProjectID, id)
+	case "tag":
+		return c.DeleteTag(ctx, wsID, id)
+	case "client":
+		return c.DeleteClient(ctx, wsID, id)
+	}
+	return fmt.Errorf("cannot delete a %s", entity)
+}
+
+// restoreEntity writes state back over the entity it was recorded from.
+func (r *registry) restoreEntity(ctx context.Context, wsID, entity string, state json.RawMessage) error {
+	c := r.client(ctx)
+	var err error
+	switch entity {
+	case "time_entry":
+		var e clockify.TimeEntry
+		json.Unmarshal(state, &e)
+		_, err = c.UpdateTimeEntry(ctx, wsID, e.ID, updateRequestFrom(e))
+	case "project":
+		var p clockify.Project
+		json.Unmarshal(state, &p)
+		_, err = c.UpdateProject(ctx, wsID, p.ID, clockify.UpdateProjectRequest{
+			Name:     p.Name,
+			ClientID: p.ClientID,
+			Billable: &p.Billable,
+			Color:    p.Color,
+			Archived: &p.Archived,
+		})
+	case "task":
+		var t clockify.Task
+		json.Unmarshal(state, &t)
+		_, err = c.UpdateTask(ctx, wsID, t.ProjectID, t.ID, clockify.UpdateTaskRequest{Name: t.Name, Billable: &t.Billable, Status: t.Status})
+	case "tag":
+		var t clockify.Tag
+		json.Unmarshal(state, &t)
+		_, err = c.UpdateTag(ctx, wsID, t.ID, clockify.UpdateTagRequest{Name: t.Name, Archived: &t.Archived})
+	case "client":
+		var cl clockify.ClockifyClient
+		json.Unmarshal(state, &cl)
+		_, err = c.UpdateClient(ctx, wsID, cl.ID, clockify.UpdateClientRequest{Name: cl.Name, Archived: &cl.Archived})
+	default:
+		err = fmt.Errorf("cannot restore a %s", entity)
+	}
+	return err
+}
+
+// recreateEntity creates a new entity with the fields of a deleted one.
+// Archived projects, tags and clients and finished tasks are archived or
+// finished again after creation.
+func (r *registry) recreateEntity(ctx context.Context, wsID, entity string, state json.RawMessage) error {
+	c := r.client(ctx)
+	switch entity {
+	case "time_entry":
+		var e clockify.TimeEntry
+		json.Unmarshal(state, &e)
+		_, err := c.CreateTimeEntry(ctx, wsID, clockify.CreateTimeEntryRequest{
+			Start:       e.TimeInterval.Start,
+			End:         e.TimeInterval.End,
+			Description: e.Description,
+			ProjectID:   e.ProjectID,
+			TaskID:      e.TaskID,
+			TagIDs:      e.TagIDs,
+			Billable:    e.Billable,
+		})
+		return err
+	case "project":
+		var p clockify.Project
+		json.Unmarshal(state, &p)
+		created, err := c.CreateProject(ctx, wsID, clockify.CreateProjectRequest{
+			Name:     p.Name,
+			ClientID: p.ClientID,
+			Billable: p.Billable,
+			Color:    p.Color,
+			IsPublic: p.Public,
+		})
+		if err != nil || !p.Archived {
+			return err
+		}
+		_, err = c.UpdateProject(ctx, wsID, created.ID, clockify.UpdateProjectRequest{Archived: &p.Archived})
+		return err
+	case "task":
+		var t clockify.Task
+		json.Unmarshal(state, &t)
+		created, err := c.CreateTask(ctx, wsID, t.ProjectID, clockify.CreateTaskRequest{Name: t.Name, Billable: t.Billable})
+		if err != nil || t.Status == "" || t.Status == created.Status {
+			return err
+		}
+		_, err = c.UpdateTask(ctx, wsID, t.ProjectID, created.ID, clockify.UpdateTaskRequest{Status: t.Status})
+		return err
+	case "tag":
+		var t clockify.Tag
+		json.Unmarshal(state, &t)
+		created, err := c.CreateTag(ctx, wsID, clockify.CreateTagRequest{Name: t.Name})
+		if err != nil || !t.Archived {
+			return err
+		}
+		_, err = c.UpdateTag(ctx, wsID, created.ID, clockify.UpdateTagRequest{Archived: &t.Archived})
+		return err
+	case "client":
+		var cl clockify.ClockifyClient
+		json.Unmarshal(state, &cl)
+		created, err := c.CreateClient(ctx, wsID, clockify.CreateClientRequest{Name: cl.Name})
+		if err != nil || !cl.Archived {
+			return err
+		}
+		_, err = c.UpdateClient(ctx, wsID, created.ID, clockify.UpdateClientRequest{Archived: &cl.Archived})
+		return err
+	}
+	return fmt.Errorf("cannot recreate a %s", entity)
+}
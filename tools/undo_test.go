@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tedyno/ticktock-mcp/audit"
+	"github.com/tedyno/ticktock-mcp/clockify"
+)
+
+// undoWorkspace stores time entries and serves their create, read, update
+// and delete endpoints, and those for the running timer.
+type undoWorkspace struct {
+	mu      sync.Mutex
+	entries map[string]clockify.TimeEntry
+	created int
+}
+
+func (f *undoWorkspace) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/user" {
+		w.Write([]byte(`{"id":"u1","name":"Ada"}`))
+		return
+	}
+	if r.URL.Path == "/workspaces/ws1/user/u1/time-entries" {
+		var running []clockify.TimeEntry
+		for _, e := range f.entries {
+			if e.TimeInterval.End == "" {
+				running = append(running, e)
+			}
+		}
+		switch {
+		case r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(running)
+		case r.Method == http.MethodPatch && len(running) == 1:
+			var body struct {
+				End string `json:"end"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			e := running[0]
+			e.TimeInterval.End = body.End
+			f.entries[e.ID] = e
+			json.NewEncoder(w).Encode(e)
+		default:
+			http.NotFound(w, r)
+		}
+		return
+	}
+	if r.Method == http.MethodPost && r.URL.Path == "/workspaces/ws1/time-entries" {
+		var req clockify.CreateTimeEntryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.created++
+		e := timeEntryFrom(fmt.Sprintf("n%d", f.created), clockify.UpdateTimeEntryRequest(req))
+		f.entries[e.ID] = e
+		json.NewEncoder(w).Encode(e)
+		return
+	}
+	id, ok := strings.CutPrefix(r.URL.Path, "/workspaces/ws1/time-entries/")
+	e, exists := f.entries[id]
+	if !ok || !exists {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		json.NewEncoder(w).Encode(e)
+	case http.MethodPut:
+		var req clockify.UpdateTimeEntryRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		f.entries[id] = timeEntryFrom(id, req)
+		json.NewEncoder(w).Encode(f.entries[id])
+	case http.MethodDelete:
+		delete(f.entries, id)
+	}
+}
+
+func timeEntryFrom(id string, req clockify.UpdateTimeEntryRequest) clockify.TimeEntry {
+	return clockify.TimeEntry{
+		ID:           id,
+		Description:  req.Description,
+		ProjectID:    req.ProjectID,
+		TaskID:       req.TaskID,
+		TagIDs:       req.TagIDs,
+		Billable:     req.Billable,
+		TimeInterval: clockify.TimeInterval{Start: req.Start, End: req.End},
+	}
+}
+
+func newUndoRegistry(t *testing.T) (*registry, *undoWorkspace) {
+	t.Helper()
+	fake := &undoWorkspace{entries: map[string]clockify.TimeEntry{"e1": storedEntry()}}
+	r := newTestRegistry(t, fake)
+	r.journal = newTestJournal(t)
+	return r, fake
+}
+
+func decodeUndo(t *testing.T, text string) undoOutput {
+	t.Helper()
+	var out undoOutput
+	// Errors put the steps on the line after the message.
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	if err := json.Unmarshal([]byte(text), &out); err != nil {
+		t.Fatalf("undo output is not JSON: %v\n%s", err, text)
+	}
+	return out
+}
+
+func TestUndo_PreviewsThenRestoresUpdates(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	update := r.withAudit("clockify_time_entry_update", timeEntryUpdateHandler(r))
+	callTool(t, update, map[string]any{"entry_id": "e1", "description": "Daily standup"})
+	callTool(t, update, map[string]any{"entry_id": "e1", "billable": false})
+
+	result := callTool(t, undoHandler(r), map[string]any{"count": 2})
+	out := decodeUndo(t, resultText(t, result))
+	if result.IsError || !out.Preview || len(out.Steps) != 2 {
+		t.Fatalf("expected a two-step preview, got %s", resultText(t, result))
+	}
+	for _, step := range out.Steps {
+		if step.Action != undoRestore || step.Refused != "" || step.Done {
+			t.Fatalf("unexpected step: %+v", step)
+		}
+	}
+	if fake.entries["e1"].Description != "Daily standup" {
+		t.Fatal("preview changed the entry")
+	}
+
+	result = callTool(t, undoHandler(r), map[string]any{"count": 2, "confirm": true})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if got := fake.entries["e1"]; got.Description != "Standup" || !got.Billable {
+		t.Fatalf("entry not restored: %+v", got)
+	}
+
+	// Both operations are marked undone, and undos are not undone in turn.
+	result = callTool(t, undoHandler(r), map[string]any{})
+	if !result.IsError || !strings.Contains(resultText(t, result), "no changes to undo") {
+		t.Fatalf("expected nothing left to undo, got %s", resultText(t, result))
+	}
+}
+
+func TestUndo_RefusesWhenEntityChangedSince(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	update := r.withAudit("clockify_time_entry_update", timeEntryUpdateHandler(r))
+	callTool(t, update, map[string]any{"entry_id": "e1", "description": "Daily standup"})
+
+	e := fake.entries["e1"]
+	e.Description = "Edited in the Clockify app"
+	fake.entries["e1"] = e
+
+	result := callTool(t, undoHandler(r), map[string]any{"confirm": true})
+	if !result.IsError || !strings.Contains(resultText(t, result), "Nothing was undone") {
+		t.Fatalf("expected a refusal, got %s", resultText(t, result))
+	}
+	if out := decodeUndo(t, resultText(t, result)); out.Steps[0].Refused != "the time entry has changed since" {
+		t.Fatalf("unexpected refusal: %+v", out.Steps[0])
+	}
+	if fake.entries["e1"].Description != "Edited in the Clockify app" {
+		t.Fatal("refused undo changed the entry")
+	}
+}
+
+func TestUndo_RecreatesDeletesAndDeletesCreates(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	callTool(t, r.withAudit("clockify_time_entry_delete", timeEntryDeleteHandler(r)), map[string]any{"entry_id": "e1"})
+	callTool(t, r.withAudit("clockify_time_entry_create", timeEntryCreateHandler(r)), map[string]any{
+		"start": "2024-03-12T13:00:00Z", "end": "2024-03-12T14:00:00Z", "description": "Oops",
+	})
+	if len(fake.entries) != 1 || fake.entries["n1"].Description != "Oops" {
+		t.Fatalf("unexpected entries before undo: %+v", fake.entries)
+	}
+
+	result := callTool(t, undoHandler(r), map[string]any{"count": 2, "confirm": true})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	out := decodeUndo(t, resultText(t, result))
+	if out.Steps[0].Action != undoDelete || out.Steps[1].Action != undoRecreate || out.Steps[1].NewID != "n2" {
+		t.Fatalf("unexpected steps: %+v", out.Steps)
+	}
+
+	want := storedEntry()
+	want.ID = "n2"
+	got, ok := fake.entries["n2"]
+	if len(fake.entries) != 1 || !ok || got.Description != want.Description || got.TaskID != want.TaskID ||
+		got.TimeInterval != want.TimeInterval || strings.Join(got.TagIDs, ",") != "g1" {
+		t.Fatalf("unexpected entries after undo: %+v", fake.entries)
+	}
+}
+
+func TestUndo_OperationID(t *testing.T) {
+	r, fake := newUndoRegistry(t)
+	update := r.withAudit("clockify_time_entry_update", timeEntryUpdateHandler(r))
+	callTool(t, update, map[string]any{"entry_id": "e1", "description": "Daily standup"})
+	callTool(t, update, map[string]any{"entry_id": "e1", "end": "2024-03-12T09:30:00Z"})
+
+	records, _ := r.journal.Query(audit.Query{})
+	first := records[1].ID
+
+	// The later change to the same entry makes the first one stale.
+	result := callTool(t, undoHandler(r), map[string]any{"operation_id": first, "confirm": true})
+	if !result.IsError || !strings.Contains(resultText(t, result), "has changed since") {
+		t.Fatalf("expected a refusal, got %s", resultText(t, result))
+	}
+
+	callTool(t, undoHandler(r), map[string]any{"confirm": true})
+	result = callTool(t, undoHandler(r), map[string]any{"operation_id": first, "confirm": true})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+	if fake.entries["e1"].Description != "Standup" {
+		t.Fatalf("entry not restored: %+v", fake.entries["e1"])
+	}
+
+	records, _ = r.journal.Query(audit.Query{})
+	for _, tt := range []struct{ id, want string }{
+		{first, "already been undone"},
+		{records[0].ID, "is an undo"},
+		{"missing", "not found"},
+	} {
+		result := callTool(t, undoHandler(r), map[string]any{"operation_id": tt.id})
+		if !result.IsError || !strings.Contains(resultText(t, result), tt.want) {
+			t.Errorf("operation_id %q: got %s, want %q", tt.id, resultText(t, result), tt.want)
+		}
+	}
+}
@@ -2,23 +2,28 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/tedyno/ticktock-mcp/clockify"
 )
 
 func registerUserTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_user_current",
 			mcp.WithDescription("Get the current authenticated user"),
+			readOnlyTool("Current user"),
+			mcp.WithOutputSchema[clockify.User](),
 		),
 		userCurrentHandler(r),
 	)
 
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_user_list",
-			mcp.WithDescription("List users in a workspace (paginated, default page 1, page_size 50)"),
+			mcp.WithDescription("List users in a workspace (paginated, default page 1, page_size 50; set all to fetch every page)"),
+			readOnlyTool("List users"),
+			mcp.WithOutputSchema[userListOutput](),
+			mcp.WithBoolean("all", mcp.Description("Fetch every page and return the complete list (ignores page and page_size)")),
 			mcp.WithNumber("page", mcp.Description("Page number (default 1)")),
 			mcp.WithNumber("page_size", mcp.Description("Number of users per page (default 50)")),
 			mcp.WithString("workspace_id", mcp.Description("Workspace ID (uses default if not provided)")),
@@ -29,9 +34,9 @@ func registerUserTools(s *server.MCPServer, r *registry) {
 
 func userCurrentHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		user, err := r.client.GetCurrentUser()
+		user, err := r.currentUser(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get current user: %v", err)), nil
+			return apiErrorResult("get current user", err, "", ""), nil
 		}
 
 		return resultJSON(user)
@@ -40,19 +45,27 @@ func userCurrentHandler(r *registry) server.ToolHandlerFunc {
 
 func userListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		wsID := r.workspaceID(req.GetString("workspace_id", ""))
+		wsID := r.workspaceID(ctx, req.GetString("workspace_id", ""))
 		if wsID == "" {
 			return mcp.NewToolResultError("workspace_id is required"), nil
 		}
 
+		if req.GetBool("all", false) {
+			users, truncated, err := clockify.Collect(r.client(ctx).AllWorkspaceUsers(ctx, wsID))
+			if err != nil {
+				return apiErrorResult("list users", err, "", wsID), nil
+			}
+			return resultJSON(userListOutput{Users: users, Truncated: truncated})
+		}
+
 		page := req.GetInt("page", 1)
 		pageSize := req.GetInt("page_size", 50)
 
-		users, err := r.client.GetWorkspaceUsers(wsID, page, pageSize)
+		users, err := r.client(ctx).GetWorkspaceUsers(ctx, wsID, page, pageSize)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list users: %v", err)), nil
+			return apiErrorResult("list users", err, "", wsID), nil
 		}
 
-		return resultJSON(map[string]any{"users": users})
+		return resultJSON(userListOutput{Users: users})
 	}
 }
@@ -2,16 +2,17 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func registerWorkspaceTools(s *server.MCPServer, r *registry) {
-	s.AddTool(
+	r.addTool(s,
 		mcp.NewTool("clockify_workspace_list",
 			mcp.WithDescription("List all workspaces available to the current user"),
+			readOnlyTool("List workspaces"),
+			mcp.WithOutputSchema[workspaceListOutput](),
 		),
 		workspaceListHandler(r),
 	)
@@ -19,11 +20,11 @@ func registerWorkspaceTools(s *server.MCPServer, r *registry) {
 
 func workspaceListHandler(r *registry) server.ToolHandlerFunc {
 	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		workspaces, err := r.client.GetWorkspaces()
+		workspaces, err := r.workspaces(ctx)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list workspaces: %v", err)), nil
+			return apiErrorResult("list workspaces", err, "", ""), nil
 		}
 
-		return resultJSON(map[string]any{"workspaces": workspaces})
+		return resultJSON(workspaceListOutput{Workspaces: workspaces})
 	}
 }